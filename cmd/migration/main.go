@@ -1,12 +1,16 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"github.com/azaliaz/quote-service/internal/storage"
 	"github.com/azaliaz/quote-service/migrations"
 	"github.com/azaliaz/quote-service/pkg/config"
+	"github.com/golang-migrate/migrate/v4"
 	"log/slog"
 	"os"
+	"strconv"
 )
 
 type Config struct {
@@ -14,6 +18,18 @@ type Config struct {
 	Path     string         `env:"PATH" yaml:"path"`
 }
 
+const usage = `Usage: migration [flags] [command]
+
+Commands:
+  up [N]      apply the next N or all pending migrations (default)
+  down N      roll back the last N migrations
+  goto V      migrate up or down to version V
+  version     print the applied version
+  force V     mark version V as applied and clean without running it (-1: no version)
+
+Flags:
+`
+
 func main() {
 	/* Configuring logger */
 	opts := &slog.HandlerOptions{
@@ -21,20 +37,198 @@ func main() {
 	}
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, opts))
 	/* Configuring flags */
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	configFile := flag.String("config-file", "none", "config file")
+	dryRun := flag.Bool("dry-run", false, "print the migrations that would run without running them")
 	flag.Parse()
 
+	cmd, arg, err := parseCommand(flag.Args(), dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	/* Parsing config */
 	cfg := Config{}
-	err := config.ReadConfig(*configFile, &cfg)
+	err = config.ReadConfig(*configFile, &cfg)
 	if err != nil {
 		logger.Error("config parse error:", slog.String("err", err.Error()))
 		os.Exit(1)
 	}
 
-	if err := migrations.PostgresMigrate(cfg.DBConfig.UrlPostgres()); err != nil {
+	m, err := migrations.NewPostgresMigrator(cfg.DBConfig.UrlPostgres())
+	if err != nil {
 		logger.Error("migration error", slog.String("err", err.Error()))
 		os.Exit(1)
 	}
-	logger.Info("migration completed", slog.String("path", cfg.Path))
+	err = run(logger, m, cmd, arg, *dryRun)
+	if closeErr := m.Close(); closeErr != nil {
+		logger.Warn("failed to close migrator", slog.String("err", closeErr.Error()))
+	}
+	if err != nil {
+		reportError(logger, err)
+		os.Exit(1)
+	}
+	logger.Info("migration completed", slog.String("command", cmd), slog.String("path", cfg.Path))
+}
+
+// parseCommand splits the arguments into a command and its numeric argument.
+// Flags may also follow the command, e.g. "down 1 -dry-run".
+func parseCommand(args []string, dryRun *bool) (string, int, error) {
+	if len(args) == 0 {
+		return "up", 0, nil
+	}
+	cmd, args := args[0], args[1:]
+
+	var arg int
+	switch cmd {
+	case "version":
+	case "up":
+		// The count is optional: "up" alone applies everything.
+		if len(args) > 0 {
+			if n, err := strconv.Atoi(args[0]); err == nil {
+				if n <= 0 {
+					return "", 0, fmt.Errorf("up: count must be positive")
+				}
+				arg, args = n, args[1:]
+			}
+		}
+	case "down", "goto", "force":
+		if len(args) == 0 {
+			return "", 0, fmt.Errorf("%s needs a version or count", cmd)
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "", 0, fmt.Errorf("%s: invalid number %q", cmd, args[0])
+		}
+		switch {
+		case cmd == "down" && n <= 0:
+			return "", 0, fmt.Errorf("down: count must be positive")
+		case cmd == "goto" && n < 0:
+			return "", 0, fmt.Errorf("goto: version cannot be negative")
+		case cmd == "force" && n < migrations.NilVersion:
+			return "", 0, fmt.Errorf("force: version must be -1 or greater")
+		}
+		arg, args = n, args[1:]
+	default:
+		return "", 0, fmt.Errorf("unknown command %q", cmd)
+	}
+
+	trailing := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	trailing.BoolVar(dryRun, "dry-run", *dryRun, "")
+	if err := trailing.Parse(args); err != nil {
+		return "", 0, err
+	}
+	if trailing.NArg() > 0 {
+		return "", 0, fmt.Errorf("%s: unexpected arguments %v", cmd, trailing.Args())
+	}
+	return cmd, arg, nil
+}
+
+func run(logger *slog.Logger, m *migrations.Migrator, cmd string, arg int, dryRun bool) error {
+	if cmd == "version" {
+		version, dirty, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			logger.Info("no migrations applied")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		logger.Info("current version", slog.Uint64("version", uint64(version)), slog.Bool("dirty", dirty))
+		return nil
+	}
+
+	if dryRun {
+		return plan(logger, m, cmd, arg)
+	}
+
+	var err error
+	switch cmd {
+	case "up":
+		if arg > 0 {
+			err = m.UpN(arg)
+		} else {
+			err = m.Up()
+		}
+	case "down":
+		err = m.Down(arg)
+	case "goto":
+		err = m.Goto(uint(arg))
+	case "force":
+		err = m.Force(arg)
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		logger.Info("no change: the database is already at the requested version")
+		return nil
+	}
+	return err
+}
+
+// plan logs the migrations cmd would run. A dirty database is reported as an
+// error because none of them could run before it is forced.
+func plan(logger *slog.Logger, m *migrations.Migrator, cmd string, arg int) error {
+	if cmd == "force" {
+		logger.Info("dry run: would force version", slog.Int("version", arg))
+		return nil
+	}
+	if version, dirty, err := m.Version(); err == nil && dirty {
+		return migrate.ErrDirty{Version: int(version)}
+	}
+
+	var (
+		steps []migrations.Step
+		err   error
+	)
+	switch cmd {
+	case "up":
+		if arg > 0 {
+			steps, err = m.PlanUpN(arg)
+		} else {
+			steps, err = m.PlanUp()
+		}
+	case "down":
+		steps, err = m.PlanDown(arg)
+	case "goto":
+		steps, err = m.PlanGoto(uint(arg))
+	}
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		logger.Info("dry run: no change, the database is already at the requested version")
+		return nil
+	}
+	for _, step := range steps {
+		logger.Info("dry run: pending migration",
+			slog.Uint64("version", uint64(step.Version)),
+			slog.String("name", step.Name),
+			slog.String("direction", step.Direction))
+	}
+	return nil
+}
+
+// reportError logs err, explaining how to recover from a dirty database.
+func reportError(logger *slog.Logger, err error) {
+	var dirty migrate.ErrDirty
+	if !errors.As(err, &dirty) {
+		var short migrate.ErrShortLimit
+		if errors.As(err, &short) {
+			logger.Error("migration error: fewer migrations applied than requested",
+				slog.Uint64("missing", uint64(short.Short)))
+			return
+		}
+		logger.Error("migration error", slog.String("err", err.Error()))
+		return
+	}
+
+	version := dirty.Version
+	logger.Error("migration error: the database is dirty, a previous migration failed half-way",
+		slog.Int("version", version),
+		slog.String("hint", fmt.Sprintf("fix the schema by hand, then run `migration force %d` "+
+			"if migration %d completed or `migration force <previous version>` if it did not", version, version)))
 }
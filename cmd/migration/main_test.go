@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCmd    string
+		wantArg    int
+		wantDryRun bool
+		wantErr    string
+	}{
+		{name: "default", wantCmd: "up"},
+		{name: "up", args: []string{"up"}, wantCmd: "up"},
+		{name: "up N", args: []string{"up", "2"}, wantCmd: "up", wantArg: 2},
+		{name: "up with a trailing dry run", args: []string{"up", "-dry-run"}, wantCmd: "up", wantDryRun: true},
+		{name: "up N with a trailing dry run", args: []string{"up", "3", "-dry-run"}, wantCmd: "up", wantArg: 3, wantDryRun: true},
+		{name: "down N", args: []string{"down", "1"}, wantCmd: "down", wantArg: 1},
+		{name: "goto V", args: []string{"goto", "7"}, wantCmd: "goto", wantArg: 7},
+		{name: "goto zero", args: []string{"goto", "0"}, wantCmd: "goto"},
+		{name: "force V", args: []string{"force", "12"}, wantCmd: "force", wantArg: 12},
+		{name: "force nil version", args: []string{"force", "-1"}, wantCmd: "force", wantArg: -1},
+		{name: "version", args: []string{"version"}, wantCmd: "version"},
+
+		{name: "unknown command", args: []string{"steps", "1"}, wantErr: `unknown command "steps"`},
+		{name: "up zero", args: []string{"up", "0"}, wantErr: "up: count must be positive"},
+		{name: "up negative", args: []string{"up", "-2"}, wantErr: "up: count must be positive"},
+		{name: "up with a word", args: []string{"up", "all"}, wantErr: "up: unexpected arguments [all]"},
+		{name: "down without count", args: []string{"down"}, wantErr: "down needs a version or count"},
+		{name: "down zero", args: []string{"down", "0"}, wantErr: "down: count must be positive"},
+		{name: "down with a word", args: []string{"down", "all"}, wantErr: `down: invalid number "all"`},
+		{name: "goto without version", args: []string{"goto"}, wantErr: "goto needs a version or count"},
+		{name: "goto negative", args: []string{"goto", "-1"}, wantErr: "goto: version cannot be negative"},
+		{name: "force below nil version", args: []string{"force", "-2"}, wantErr: "force: version must be -1 or greater"},
+		{name: "version with an argument", args: []string{"version", "3"}, wantErr: "version: unexpected arguments [3]"},
+		{name: "extra argument", args: []string{"down", "1", "2"}, wantErr: "down: unexpected arguments [2]"},
+		{name: "unknown trailing flag", args: []string{"down", "1", "-force"}, wantErr: "flag provided but not defined: -force"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dryRun := false
+			cmd, arg, err := parseCommand(tt.args, &dryRun)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCmd, cmd)
+			assert.Equal(t, tt.wantArg, arg)
+			assert.Equal(t, tt.wantDryRun, dryRun)
+		})
+	}
+
+	t.Run("keeps a leading dry run", func(t *testing.T) {
+		dryRun := true
+		_, _, err := parseCommand([]string{"down", "1"}, &dryRun)
+		require.NoError(t, err)
+		assert.True(t, dryRun)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/azaliaz/quote-service/pkg/service"
 	"log/slog"
 	"os"
+	_ "time/tzdata" // APP_DAILY_TIMEZONE must resolve in images without a zoneinfo database
 )
 
 type Config struct {
@@ -43,6 +44,7 @@ func main() {
 
 	mgr := service.NewManager(logger)
 	mgr.AddService(db, app, api)
+	api.Health = mgr
 
 	ctx := context.Background()
 	if err := mgr.Run(ctx); err != nil {
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/azaliaz/quote-service/internal/application"
+)
+
+// importContentTypes are the Content-Types POST /quotes/import expects.
+var importContentTypes = map[application.ImportFormat]string{
+	application.ImportJSON:   "application/json",
+	application.ImportNDJSON: "application/x-ndjson",
+	application.ImportCSV:    "text/csv",
+}
+
+// apiClient implements backend over the REST API of quote-service.
+type apiClient struct {
+	base  *url.URL
+	token string
+	http  *http.Client
+}
+
+func newAPIClient(rawURL, token string) (*apiClient, error) {
+	base, err := url.Parse(strings.TrimSuffix(rawURL, "/"))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid API URL %q", rawURL)
+	}
+	// No client timeout: imports and exports stream for as long as they need,
+	// and an interrupt cancels the request context.
+	return &apiClient{base: base, token: token, http: &http.Client{}}, nil
+}
+
+// apiError is an error response of the REST API.
+type apiError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details []struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	} `json:"details"`
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Message)
+	for _, d := range e.Details {
+		msg += fmt.Sprintf("; %s %s", d.Field, d.Message)
+	}
+	return msg
+}
+
+// do sends a request and returns the response of a successful one; error
+// responses are decoded into an *apiError.
+func (c *apiClient) do(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader) (*http.Response, error) {
+	u := c.base.JoinPath(path)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+
+	// nolint: errcheck
+	defer resp.Body.Close()
+	apiErr := &apiError{Status: resp.StatusCode}
+	if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Code == "" {
+		apiErr.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+		apiErr.Message = "unexpected response"
+	}
+	return nil, apiErr
+}
+
+// doJSON sends a request and decodes a JSON response into out.
+func (c *apiClient) doJSON(ctx context.Context, method, path string, query url.Values, header http.Header, body io.Reader, out any) error {
+	resp, err := c.do(ctx, method, path, query, header, body)
+	if err != nil {
+		return err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// filterQuery encodes the author and tag filters of GET /quotes.
+func filterQuery(author string, tags []string, matchAll bool) url.Values {
+	query := url.Values{}
+	if author != "" {
+		query.Set("author", author)
+	}
+	for _, tag := range tags {
+		query.Add("tag", tag)
+	}
+	if matchAll {
+		query.Set("tag_mode", "all")
+	}
+	return query
+}
+
+func setPositive(query url.Values, key string, v int) {
+	if v > 0 {
+		query.Set(key, strconv.Itoa(v))
+	}
+}
+
+func (c *apiClient) AddQuote(ctx context.Context, req *application.AddQuoteRequest) (*application.AddQuoteResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if req.Upsert {
+		query.Set("upsert", "true")
+	}
+
+	header := http.Header{"Content-Type": {"application/json"}}
+	resp, err := c.do(ctx, http.MethodPost, "/quotes", query, header, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+
+	var added struct {
+		ID      int64 `json:"id"`
+		Created bool  `json:"created"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&added); err != nil {
+		return nil, err
+	}
+	// The ETag is the quoted version; an unexpected one leaves Version at zero.
+	version, _ := strconv.Atoi(strings.Trim(resp.Header.Get("ETag"), `"`))
+	return &application.AddQuoteResponse{ID: added.ID, Created: added.Created, Version: version}, nil
+}
+
+func (c *apiClient) GetQuotes(ctx context.Context, req *application.GetQuotesRequest) (*application.GetQuotesResponse, error) {
+	query := filterQuery(req.Author, req.Tags, req.MatchAllTags)
+	setPositive(query, "limit", req.Limit)
+	if req.Cursor != "" {
+		query.Set("cursor", req.Cursor)
+	}
+
+	var page quoteList
+	if err := c.doJSON(ctx, http.MethodGet, "/quotes", query, nil, nil, &page); err != nil {
+		return nil, err
+	}
+	return &application.GetQuotesResponse{Quotes: page.Quotes, NextCursor: page.NextCursor}, nil
+}
+
+func (c *apiClient) SearchQuotes(ctx context.Context, req *application.SearchQuotesRequest) (*application.SearchQuotesResponse, error) {
+	query := url.Values{"q": {req.Query}}
+	setPositive(query, "limit", req.Limit)
+	setPositive(query, "offset", req.Offset)
+
+	var resp application.SearchQuotesResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/quotes/search", query, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *apiClient) DeleteQuote(ctx context.Context, req *application.DeleteQuoteRequest) (*application.DeleteQuoteResponse, error) {
+	header := http.Header{}
+	tags := make([]string, 0, len(req.IfVersions))
+	for _, v := range req.IfVersions {
+		tags = append(tags, strconv.Quote(strconv.Itoa(v)))
+	}
+	if len(tags) > 0 {
+		header.Set("If-Match", strings.Join(tags, ", "))
+	}
+	resp, err := c.do(ctx, http.MethodDelete, "/quotes/"+strconv.FormatInt(req.ID, 10), nil, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	// nolint: errcheck
+	resp.Body.Close()
+	return &application.DeleteQuoteResponse{Success: true}, nil
+}
+
+func (c *apiClient) ImportQuotes(ctx context.Context, req *application.ImportQuotesRequest) (*application.ImportQuotesResponse, error) {
+	contentType, ok := importContentTypes[req.Format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported import format %q", req.Format)
+	}
+	query := url.Values{}
+	if req.DryRun {
+		query.Set("dry_run", "true")
+	}
+
+	var resp application.ImportQuotesResponse
+	header := http.Header{"Content-Type": {contentType}}
+	if err := c.doJSON(ctx, http.MethodPost, "/quotes/import", query, header, req.Body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *apiClient) ExportQuotes(ctx context.Context, req *application.ExportQuotesRequest) error {
+	query := filterQuery(req.Author, req.Tags, req.MatchAllTags)
+	query.Set("format", string(req.Format))
+
+	resp, err := c.do(ctx, http.MethodGet, "/quotes/export", query, nil, nil)
+	if err != nil {
+		return err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+	_, err = io.Copy(req.Out, resp.Body)
+	return err
+}
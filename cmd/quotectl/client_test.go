@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/azaliaz/quote-service/internal/application"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns an apiClient talking to handler with the token "secret".
+func newTestClient(t *testing.T, handler http.HandlerFunc) *apiClient {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := newAPIClient(srv.URL+"/", "secret")
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewAPIClient(t *testing.T) {
+	for _, rawURL := range []string{"", "localhost:8080", "http://", "://x"} {
+		_, err := newAPIClient(rawURL, "")
+		assert.Error(t, err, rawURL)
+	}
+}
+
+func TestAPIClientAddQuote(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/quotes", r.URL.Path)
+			assert.Empty(t, r.URL.RawQuery)
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"author":"Seneca","quote":"Luck","tags":["luck"]}`, string(body))
+
+			w.Header().Set("ETag", `"1"`)
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"id":42,"created":true}`)
+		})
+
+		resp, err := c.AddQuote(context.Background(), &application.AddQuoteRequest{Author: "Seneca", Quote: "Luck", Tags: []string{"luck"}})
+		require.NoError(t, err)
+		assert.Equal(t, &application.AddQuoteResponse{ID: 42, Created: true, Version: 1}, resp)
+	})
+
+	t.Run("upsert finds the stored quote", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "true", r.URL.Query().Get("upsert"))
+
+			w.Header().Set("ETag", `"3"`)
+			io.WriteString(w, `{"id":7,"created":false}`)
+		})
+
+		resp, err := c.AddQuote(context.Background(), &application.AddQuoteRequest{Author: "Seneca", Quote: "Luck", Upsert: true})
+		require.NoError(t, err)
+		assert.Equal(t, &application.AddQuoteResponse{ID: 7, Version: 3}, resp)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			io.WriteString(w, `{"code":"conflict","message":"quote already exists"}`)
+		})
+
+		_, err := c.AddQuote(context.Background(), &application.AddQuoteRequest{Author: "Seneca", Quote: "Luck"})
+		var apiErr *apiError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.Status)
+		assert.Equal(t, "conflict", apiErr.Code)
+	})
+}
+
+func TestAPIClientErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{
+			name:   "error envelope",
+			status: http.StatusBadRequest,
+			body:   `{"code":"validation_failed","message":"invalid quote","details":[{"field":"author","message":"is required"},{"field":"quote","message":"is too long"}]}`,
+			want:   "400 validation_failed: invalid quote; author is required; quote is too long",
+		},
+		{
+			name:   "not JSON",
+			status: http.StatusBadGateway,
+			body:   "<html>bad gateway</html>",
+			want:   "502 bad_gateway: unexpected response",
+		},
+		{
+			name:   "JSON without a code",
+			status: http.StatusServiceUnavailable,
+			body:   `{"error":"down"}`,
+			want:   "503 service_unavailable: unexpected response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			})
+
+			_, err := c.GetQuotes(context.Background(), &application.GetQuotesRequest{})
+			var apiErr *apiError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.Status)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestAPIClientRequests(t *testing.T) {
+	t.Run("list filters", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/quotes", r.URL.Path)
+			q := r.URL.Query()
+			assert.Equal(t, "Seneca", q.Get("author"))
+			assert.Equal(t, []string{"luck", "life"}, q["tag"])
+			assert.Equal(t, "all", q.Get("tag_mode"))
+			assert.Equal(t, "10", q.Get("limit"))
+			assert.Equal(t, "abc", q.Get("cursor"))
+			io.WriteString(w, `{"quotes":[{"id":1,"author":"Seneca","quote":"Luck"}],"next_cursor":"def"}`)
+		})
+
+		resp, err := c.GetQuotes(context.Background(), &application.GetQuotesRequest{
+			Author:       "Seneca",
+			Tags:         []string{"luck", "life"},
+			MatchAllTags: true,
+			Limit:        10,
+			Cursor:       "abc",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "def", resp.NextCursor)
+		require.Len(t, resp.Quotes, 1)
+		assert.Equal(t, int64(1), resp.Quotes[0].ID)
+	})
+
+	t.Run("conditional delete", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/quotes/5", r.URL.Path)
+			assert.Equal(t, `"2", "3"`, r.Header.Get("If-Match"))
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		_, err := c.DeleteQuote(context.Background(), &application.DeleteQuoteRequest{ID: 5, IfVersions: []int{2, 3}})
+		require.NoError(t, err)
+	})
+}
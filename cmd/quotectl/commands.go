@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/azaliaz/quote-service/internal/application"
+)
+
+// backend is the part of application.QuoteService quotectl uses. It is
+// implemented by application.Service for the database and by apiClient for the REST API.
+type backend interface {
+	AddQuote(ctx context.Context, req *application.AddQuoteRequest) (*application.AddQuoteResponse, error)
+	GetQuotes(ctx context.Context, req *application.GetQuotesRequest) (*application.GetQuotesResponse, error)
+	SearchQuotes(ctx context.Context, req *application.SearchQuotesRequest) (*application.SearchQuotesResponse, error)
+	DeleteQuote(ctx context.Context, req *application.DeleteQuoteRequest) (*application.DeleteQuoteResponse, error)
+	ImportQuotes(ctx context.Context, req *application.ImportQuotesRequest) (*application.ImportQuotesResponse, error)
+	ExportQuotes(ctx context.Context, req *application.ExportQuotesRequest) error
+}
+
+// command runs one quotectl command. The backend is connected only once the
+// command line is valid, so usage errors and -h never touch the database.
+type command struct {
+	connect func() (backend, error)
+	out     *printer
+}
+
+func (c *command) run(ctx context.Context, name string, args []string) error {
+	switch name {
+	case "add":
+		return c.add(ctx, args)
+	case "list":
+		return c.list(ctx, args)
+	case "search":
+		return c.search(ctx, args)
+	case "delete":
+		return c.delete(ctx, args)
+	case "import":
+		return c.importQuotes(ctx, args)
+	case "export":
+		return c.export(ctx, args)
+	}
+	fmt.Fprintf(os.Stderr, "quotectl: unknown command %q, run quotectl -h for the list of commands\n", name)
+	return errUsage
+}
+
+// newFlagSet returns a flag set for a command whose usage line is "quotectl name args".
+func newFlagSet(name, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: quotectl %s %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses args and marks any failure as a usage error.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	return nil
+}
+
+// usageError prints msg with the usage of the command.
+func usageError(flags *flag.FlagSet, msg string) error {
+	fmt.Fprintf(flags.Output(), "quotectl %s: %s\n", flags.Name(), msg)
+	flags.Usage()
+	return errUsage
+}
+
+// stringList is a flag that may be repeated.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// tagFlags registers the -tag and -tag-mode filters shared by list and export.
+func tagFlags(flags *flag.FlagSet) (*stringList, *string) {
+	tags := &stringList{}
+	flags.Var(tags, "tag", "filter by tag, may be repeated")
+	mode := flags.String("tag-mode", "any", "any: quotes with any of the tags, all: with all of them")
+	return tags, mode
+}
+
+func validTagMode(flags *flag.FlagSet, mode string) error {
+	if mode != "any" && mode != "all" {
+		return usageError(flags, "-tag-mode must be any or all")
+	}
+	return nil
+}
+
+func (c *command) add(ctx context.Context, args []string) error {
+	flags := newFlagSet("add", "-author AUTHOR -quote TEXT [-tag TAG]... [-lang CODE] [-upsert]")
+	author := flags.String("author", "", "author of the quote")
+	text := flags.String("quote", "", "text of the quote")
+	lang := flags.String("lang", "", "ISO 639 language code")
+	upsert := flags.Bool("upsert", false, "report the stored quote instead of failing on a duplicate")
+	tags := &stringList{}
+	flags.Var(tags, "tag", "tag of the quote, may be repeated")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *author == "" || *text == "" {
+		return usageError(flags, "-author and -quote are required")
+	}
+
+	b, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.AddQuote(ctx, &application.AddQuoteRequest{
+		Author: *author,
+		Quote:  *text,
+		Tags:   *tags,
+		Lang:   *lang,
+		Upsert: *upsert,
+	})
+	if err != nil {
+		return err
+	}
+	return c.out.added(resp)
+}
+
+func (c *command) list(ctx context.Context, args []string) error {
+	flags := newFlagSet("list", "[-author AUTHOR] [-tag TAG]... [-tag-mode any|all] [-limit N] [-cursor CURSOR]")
+	author := flags.String("author", "", "filter by author or one of their aliases")
+	tags, mode := tagFlags(flags)
+	limit := flags.Int("limit", 0, "page size (default 50)")
+	cursor := flags.String("cursor", "", "next_cursor of the previous page")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := validTagMode(flags, *mode); err != nil {
+		return err
+	}
+
+	b, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.GetQuotes(ctx, &application.GetQuotesRequest{
+		Author:       *author,
+		Tags:         *tags,
+		MatchAllTags: *mode == "all",
+		Limit:        *limit,
+		Cursor:       *cursor,
+	})
+	if err != nil {
+		return err
+	}
+	return c.out.quotes(quoteList{Quotes: resp.Quotes, NextCursor: resp.NextCursor})
+}
+
+func (c *command) search(ctx context.Context, args []string) error {
+	flags := newFlagSet("search", "[-limit N] [-offset N] QUERY...")
+	limit := flags.Int("limit", 0, "number of results (default 50)")
+	offset := flags.Int("offset", 0, "number of results to skip")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	query := strings.Join(flags.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		return usageError(flags, "a search query is required")
+	}
+
+	b, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.SearchQuotes(ctx, &application.SearchQuotesRequest{Query: query, Limit: *limit, Offset: *offset})
+	if err != nil {
+		return err
+	}
+	return c.out.searchResults(resp.Results)
+}
+
+func (c *command) delete(ctx context.Context, args []string) error {
+	flags := newFlagSet("delete", "[-if-version N] ID...")
+	version := flags.Int("if-version", 0, "only delete if the quote still has this version")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return usageError(flags, "at least one quote ID is required")
+	}
+	ids := make([]int64, 0, flags.NArg())
+	for _, arg := range flags.Args() {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || id <= 0 {
+			return usageError(flags, fmt.Sprintf("invalid quote ID %q", arg))
+		}
+		ids = append(ids, id)
+	}
+
+	b, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	var versions []int
+	if *version != 0 {
+		versions = []int{*version}
+	}
+	deleted := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, err := b.DeleteQuote(ctx, &application.DeleteQuoteRequest{ID: id, IfVersions: versions}); err != nil {
+			// Report what was done before the failure so a rerun can skip it.
+			if printErr := c.out.deleted(deleted); printErr != nil {
+				return printErr
+			}
+			return fmt.Errorf("quote %d: %w", id, err)
+		}
+		deleted = append(deleted, id)
+	}
+	return c.out.deleted(deleted)
+}
+
+func (c *command) importQuotes(ctx context.Context, args []string) error {
+	flags := newFlagSet("import", "[-format json|ndjson|csv] [-dry-run] FILE|-")
+	format := flags.String("format", "", "format of the file (default: taken from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "validate and report without storing anything")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return usageError(flags, "exactly one file is required, - reads standard input")
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+		if *format == "jsonl" {
+			*format = string(application.ImportNDJSON)
+		}
+	}
+	switch application.ImportFormat(*format) {
+	case application.ImportJSON, application.ImportNDJSON, application.ImportCSV:
+	default:
+		return usageError(flags, "-format must be json, ndjson or csv")
+	}
+
+	var body io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		// nolint: errcheck
+		defer f.Close()
+		body = f
+	}
+
+	b, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.ImportQuotes(ctx, &application.ImportQuotesRequest{
+		Format: application.ImportFormat(*format),
+		Body:   body,
+		DryRun: *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	return c.out.importReport(resp)
+}
+
+func (c *command) export(ctx context.Context, args []string) error {
+	flags := newFlagSet("export", "[-format json|ndjson|csv|markdown] [-author AUTHOR] [-tag TAG]... [-tag-mode any|all] [-o FILE]")
+	format := flags.String("format", string(application.ExportJSON), "json, ndjson, csv or markdown")
+	author := flags.String("author", "", "filter by author or one of their aliases")
+	tags, mode := tagFlags(flags)
+	path := flags.String("o", "", "write to FILE instead of standard output")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := validTagMode(flags, *mode); err != nil {
+		return err
+	}
+
+	b, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	req := &application.ExportQuotesRequest{
+		Format:       application.ExportFormat(*format),
+		Author:       *author,
+		Tags:         *tags,
+		MatchAllTags: *mode == "all",
+		Out:          os.Stdout,
+	}
+	if *path == "" {
+		return b.ExportQuotes(ctx, req)
+	}
+	return writeFile(*path, func(w io.Writer) error {
+		req.Out = w
+		return b.ExportQuotes(ctx, req)
+	})
+}
+
+// writeFile writes path through a temporary file next to it and renames it
+// into place only once write succeeds, so a failed export never leaves a
+// truncated file or clobbers the previous one.
+func writeFile(path string, write func(w io.Writer) error) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			// nolint: errcheck
+			f.Close()
+			// nolint: errcheck
+			os.Remove(f.Name())
+		}
+	}()
+
+	// CreateTemp makes the file private; an export is as readable as os.Create would make it.
+	if err := f.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azaliaz/quote-service/internal/application"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend records the requests of a command and answers them with empty results.
+type fakeBackend struct {
+	requests  []any
+	exportErr error
+}
+
+func (f *fakeBackend) AddQuote(_ context.Context, req *application.AddQuoteRequest) (*application.AddQuoteResponse, error) {
+	f.requests = append(f.requests, req)
+	return &application.AddQuoteResponse{ID: 1, Created: true, Version: 1}, nil
+}
+
+func (f *fakeBackend) GetQuotes(_ context.Context, req *application.GetQuotesRequest) (*application.GetQuotesResponse, error) {
+	f.requests = append(f.requests, req)
+	return &application.GetQuotesResponse{}, nil
+}
+
+func (f *fakeBackend) SearchQuotes(_ context.Context, req *application.SearchQuotesRequest) (*application.SearchQuotesResponse, error) {
+	f.requests = append(f.requests, req)
+	return &application.SearchQuotesResponse{}, nil
+}
+
+func (f *fakeBackend) DeleteQuote(_ context.Context, req *application.DeleteQuoteRequest) (*application.DeleteQuoteResponse, error) {
+	f.requests = append(f.requests, req)
+	return &application.DeleteQuoteResponse{Success: true}, nil
+}
+
+func (f *fakeBackend) ImportQuotes(_ context.Context, req *application.ImportQuotesRequest) (*application.ImportQuotesResponse, error) {
+	req.Body = nil
+	f.requests = append(f.requests, req)
+	return &application.ImportQuotesResponse{}, nil
+}
+
+func (f *fakeBackend) ExportQuotes(_ context.Context, req *application.ExportQuotesRequest) error {
+	if _, err := io.WriteString(req.Out, "exported"); err != nil {
+		return err
+	}
+	req.Out = nil
+	f.requests = append(f.requests, req)
+	return f.exportErr
+}
+
+func newTestCommand(b *fakeBackend) *command {
+	return &command{
+		connect: func() (backend, error) { return b, nil },
+		out:     newPrinter(io.Discard, outputTable),
+	}
+}
+
+func TestCommandUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  string
+		args []string
+	}{
+		{name: "unknown command", cmd: "frobnicate"},
+		{name: "unknown flag", cmd: "list", args: []string{"-color"}},
+		{name: "add without author", cmd: "add", args: []string{"-quote", "Luck"}},
+		{name: "add without quote", cmd: "add", args: []string{"-author", "Seneca"}},
+		{name: "invalid tag mode", cmd: "list", args: []string{"-tag-mode", "some"}},
+		{name: "search without query", cmd: "search", args: []string{"-limit", "5", " "}},
+		{name: "delete without ids", cmd: "delete", args: []string{"-if-version", "2"}},
+		{name: "delete with a bad id", cmd: "delete", args: []string{"1", "x"}},
+		{name: "delete with a negative id", cmd: "delete", args: []string{"--", "-1"}},
+		{name: "invalid limit", cmd: "search", args: []string{"-limit", "ten", "luck"}},
+		{name: "import without file", cmd: "import"},
+		{name: "import with two files", cmd: "import", args: []string{"a.json", "b.json"}},
+		{name: "import of unknown format", cmd: "import", args: []string{"quotes.xml"}},
+		{name: "export with invalid tag mode", cmd: "export", args: []string{"-tag-mode", "none"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connected := false
+			c := &command{
+				connect: func() (backend, error) {
+					connected = true
+					return &fakeBackend{}, nil
+				},
+				out: newPrinter(io.Discard, outputTable),
+			}
+
+			err := c.run(context.Background(), tt.cmd, tt.args)
+			assert.ErrorIs(t, err, errUsage)
+			assert.Equal(t, 2, exitCode(err))
+			assert.False(t, connected, "usage errors must not connect")
+		})
+	}
+}
+
+func TestCommandRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.jsonl")
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+
+	tests := []struct {
+		name string
+		cmd  string
+		args []string
+		want []any
+	}{
+		{
+			name: "add",
+			cmd:  "add",
+			args: []string{"-author", "Seneca", "-quote", "Luck", "-tag", "luck", "-tag", "life", "-lang", "en", "-upsert"},
+			want: []any{&application.AddQuoteRequest{Author: "Seneca", Quote: "Luck", Tags: []string{"luck", "life"}, Lang: "en", Upsert: true}},
+		},
+		{
+			name: "list",
+			cmd:  "list",
+			args: []string{"-author", "Seneca", "-tag", "luck", "-tag-mode", "all", "-limit", "10", "-cursor", "abc"},
+			want: []any{&application.GetQuotesRequest{Author: "Seneca", Tags: []string{"luck"}, MatchAllTags: true, Limit: 10, Cursor: "abc"}},
+		},
+		{
+			name: "search joins the arguments",
+			cmd:  "search",
+			args: []string{"-offset", "5", "fortune", "favors"},
+			want: []any{&application.SearchQuotesRequest{Query: "fortune favors", Offset: 5}},
+		},
+		{
+			name: "delete",
+			cmd:  "delete",
+			args: []string{"1", "2"},
+			want: []any{&application.DeleteQuoteRequest{ID: 1}, &application.DeleteQuoteRequest{ID: 2}},
+		},
+		{
+			name: "conditional delete",
+			cmd:  "delete",
+			args: []string{"-if-version", "3", "1"},
+			want: []any{&application.DeleteQuoteRequest{ID: 1, IfVersions: []int{3}}},
+		},
+		{
+			name: "import takes the format from the extension",
+			cmd:  "import",
+			args: []string{"-dry-run", path},
+			want: []any{&application.ImportQuotesRequest{Format: application.ImportNDJSON, DryRun: true}},
+		},
+		{
+			name: "import with an explicit format",
+			cmd:  "import",
+			args: []string{"-format", "csv", path},
+			want: []any{&application.ImportQuotesRequest{Format: application.ImportCSV}},
+		},
+		{
+			name: "export",
+			cmd:  "export",
+			args: []string{"-format", "csv", "-author", "Seneca", "-tag", "luck"},
+			want: []any{&application.ExportQuotesRequest{Format: application.ExportCSV, Author: "Seneca", Tags: []string{"luck"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &fakeBackend{}
+			c := newTestCommand(b)
+			if tt.cmd == "export" {
+				// Keep the exported text out of the test output.
+				path := filepath.Join(t.TempDir(), "out.csv")
+				tt.args = append(tt.args, "-o", path)
+			}
+
+			require.NoError(t, c.run(context.Background(), tt.cmd, tt.args))
+			assert.Equal(t, tt.want, b.requests)
+		})
+	}
+}
+
+func TestExportToFile(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "quotes.json")
+		require.NoError(t, os.WriteFile(path, []byte("previous"), 0o644))
+
+		require.NoError(t, newTestCommand(&fakeBackend{}).run(context.Background(), "export", []string{"-o", path}))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "exported", string(data))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "the temporary file must be renamed")
+	})
+
+	t.Run("failure keeps the previous file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "quotes.json")
+		require.NoError(t, os.WriteFile(path, []byte("previous"), 0o644))
+		exportErr := errors.New("connection reset")
+
+		err := newTestCommand(&fakeBackend{exportErr: exportErr}).run(context.Background(), "export", []string{"-o", path})
+		assert.ErrorIs(t, err, exportErr)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "previous", string(data))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "the temporary file must be removed")
+	})
+
+	t.Run("failure creates no file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "quotes.json")
+
+		err := newTestCommand(&fakeBackend{exportErr: errors.New("timeout")}).run(context.Background(), "export", []string{"-o", path})
+		assert.Error(t, err)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestPrinterAdded(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, newPrinter(&out, outputJSON).added(&application.AddQuoteResponse{ID: 7, Version: 3}))
+	assert.JSONEq(t, `{"id":7,"created":false}`, out.String())
+
+	out.Reset()
+	require.NoError(t, newPrinter(&out, outputTable).added(&application.AddQuoteResponse{ID: 42, Created: true}))
+	assert.Equal(t, "42\n", out.String())
+}
+
+func TestRunExitCodes(t *testing.T) {
+	assert.Equal(t, 2, run(nil), "no command")
+	assert.Equal(t, 2, run([]string{"-output", "xml", "list"}), "unknown output format")
+	assert.Equal(t, 2, run([]string{"-unknown"}), "unknown flag")
+	assert.Equal(t, 0, run([]string{"-h"}), "help")
+	assert.Equal(t, 0, run([]string{"list", "-h"}), "command help")
+}
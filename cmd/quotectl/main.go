@@ -0,0 +1,128 @@
+// Command quotectl runs maintenance operations on the quote collection, either
+// directly against the database or through the REST API of quote-service.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/azaliaz/quote-service/internal/application"
+	"github.com/azaliaz/quote-service/internal/storage"
+	"github.com/azaliaz/quote-service/pkg/config"
+	"log/slog"
+	"os"
+	"os/signal"
+)
+
+// Config is read like the quote-service one, so quotectl can run next to it
+// with the same environment. API switches to the REST API when set.
+type Config struct {
+	App     application.Config `envPrefix:"APP_" yaml:"app"`
+	Storage storage.Config     `envPrefix:"STORAGE_" yaml:"storage"`
+	API     string             `env:"QUOTECTL_API" yaml:"api"`
+	Token   string             `env:"QUOTECTL_TOKEN" yaml:"token"`
+}
+
+const usage = `Usage: quotectl [flags] <command> [command flags] [args]
+
+Commands:
+  add      add a quote
+  list     list quotes
+  search   full-text search
+  delete   move quotes to the trash
+  import   import quotes from a JSON, NDJSON or CSV file
+  export   export quotes as JSON, NDJSON, CSV or Markdown
+
+Run "quotectl <command> -h" for the flags of a command.
+
+Flags:
+`
+
+// errUsage reports a command line error that was already explained to the user.
+var errUsage = errors.New("usage error")
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the command line and returns the process exit code:
+// 1 for a failed command, 2 for a usage error.
+func run(args []string) int {
+	flags := flag.NewFlagSet("quotectl", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config-file", "none", "config file")
+	api := flags.String("api", "", "base URL of the REST API, e.g. http://localhost:8080 (default: connect to the database)")
+	token := flags.String("token", "", "bearer token for the REST API")
+	subject := flags.String("as", "quotectl", "actor recorded in the audit log when working against the database")
+	output := flags.String("output", outputTable, "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return exitCode(fmt.Errorf("%w: %w", errUsage, err))
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	if *output != outputTable && *output != outputJSON {
+		fmt.Fprintf(os.Stderr, "quotectl: unknown output format %q\n", *output)
+		return 2
+	}
+
+	cfg := Config{}
+	if err := config.ReadConfig(*configFile, &cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "quotectl: config parse error: %v\n", err)
+		return 1
+	}
+	if *api != "" {
+		cfg.API = *api
+	}
+	if *token != "" {
+		cfg.Token = *token
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	// The principal only matters against the database: the API authenticates the token.
+	ctx = application.WithPrincipal(ctx, &application.Principal{Subject: *subject, Role: application.RoleAdmin})
+
+	var db *storage.DB
+	defer func() {
+		if db != nil {
+			db.Stop()
+		}
+	}()
+	connect := func() (backend, error) {
+		if cfg.API != "" {
+			return newAPIClient(cfg.API, cfg.Token)
+		}
+		// Logs go to stderr so they never mix with the command output.
+		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+		db = storage.NewDB(&cfg.Storage, logger)
+		if err := db.Init(); err != nil {
+			db = nil
+			return nil, err
+		}
+		return application.NewService(logger, &cfg.App, storage.NewService(db, logger)), nil
+	}
+
+	cmd := &command{connect: connect, out: newPrinter(os.Stdout, *output)}
+	err := cmd.run(ctx, flags.Arg(0), flags.Args()[1:])
+	if err != nil && !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "quotectl:", err)
+	}
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		return 1
+	}
+}
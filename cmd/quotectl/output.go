@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/azaliaz/quote-service/internal/application"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+
+	// maxQuoteWidth is where quote text is cut in table output.
+	maxQuoteWidth = 60
+)
+
+// printer writes command results either as aligned tables or as JSON shaped
+// like the REST API responses.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(w io.Writer, format string) *printer {
+	return &printer{w: w, json: format == outputJSON}
+}
+
+type quoteList struct {
+	Quotes     []application.Quote `json:"quotes"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// added prints the id of an added quote, or of the stored one an upsert found.
+func (p *printer) added(resp *application.AddQuoteResponse) error {
+	if p.json {
+		return p.writeJSON(struct {
+			ID      int64 `json:"id"`
+			Created bool  `json:"created"`
+		}{ID: resp.ID, Created: resp.Created})
+	}
+	if !resp.Created {
+		// Like the next page cursor, the note goes to stderr to keep the id easy to pipe.
+		fmt.Fprintf(os.Stderr, "quote %d is already stored\n", resp.ID)
+	}
+	_, err := fmt.Fprintln(p.w, resp.ID)
+	return err
+}
+
+func (p *printer) quotes(list quoteList) error {
+	if p.json {
+		return p.writeJSON(list)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tAUTHOR\tQUOTE\tTAGS")
+	for _, q := range list.Quotes {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", q.ID, q.Author, shorten(q.Quote), strings.Join(q.Tags, ","))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if list.NextCursor != "" {
+		// The cursor goes to stderr so the table stays easy to pipe.
+		fmt.Fprintf(os.Stderr, "next page: -cursor %s\n", list.NextCursor)
+	}
+	return nil
+}
+
+func (p *printer) searchResults(results []application.SearchResult) error {
+	if p.json {
+		return p.writeJSON(struct {
+			Results []application.SearchResult `json:"results"`
+		}{Results: results})
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tRANK\tAUTHOR\tQUOTE")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%d\t%.3f\t%s\t%s\n", r.Quote.ID, r.Rank, r.Quote.Author, shorten(r.Quote.Quote))
+	}
+	return tw.Flush()
+}
+
+func (p *printer) deleted(ids []int64) error {
+	if p.json {
+		return p.writeJSON(struct {
+			Deleted []int64 `json:"deleted"`
+		}{Deleted: ids})
+	}
+	for _, id := range ids {
+		if _, err := fmt.Fprintf(p.w, "deleted %d\n", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importReport lists the rows that were not inserted, followed by the totals.
+func (p *printer) importReport(resp *application.ImportQuotesResponse) error {
+	if p.json {
+		return p.writeJSON(resp)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROW\tSTATUS\tID\tERROR")
+	for _, row := range resp.Rows {
+		if row.Status == application.ImportInserted {
+			continue
+		}
+		id := ""
+		if row.ID != 0 {
+			id = fmt.Sprint(row.ID)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", row.Row, row.Status, id, row.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	summary := "inserted %d, duplicates %d, invalid %d\n"
+	if resp.DryRun {
+		summary = "dry run: would insert %d, duplicates %d, invalid %d\n"
+	}
+	_, err := fmt.Fprintf(p.w, summary, resp.Inserted, resp.Duplicates, resp.Invalid)
+	return err
+}
+
+func (p *printer) writeJSON(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// shorten puts text on one line and cuts it to maxQuoteWidth characters.
+func shorten(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= maxQuoteWidth {
+		return text
+	}
+	return string([]rune(text)[:maxQuoteWidth-1]) + "…"
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+)
+
+// purgeActor is recorded as the actor of quotes removed by the purge job.
+const purgeActor = "trash-purge"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the id of the request being served.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the id stored by WithRequestID, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// withActor attributes the storage mutations made with ctx to subject,
+// so they are audited together with the request id.
+func withActor(ctx context.Context, subject string) context.Context {
+	return storage.WithActor(ctx, storage.Actor{Subject: subject, RequestID: RequestIDFromContext(ctx)})
+}
+
+func toAppAuditEvent(se *storage.AuditEvent) AuditEvent {
+	return AuditEvent{
+		ID:        se.ID,
+		QuoteID:   se.QuoteID,
+		Actor:     se.Actor,
+		Action:    se.Action,
+		Before:    se.Before,
+		After:     se.After,
+		RequestID: se.RequestID,
+		CreatedAt: se.CreatedAt,
+	}
+}
+
+// GetAuditEvents lists recorded quote mutations, newest first. Admins only.
+func (s *Service) GetAuditEvents(ctx context.Context, req *GetAuditEventsRequest) (*GetAuditEventsResponse, error) {
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+	if req.Offset < 0 {
+		return nil, newValidationError("offset", "offset cannot be negative")
+	}
+	if req.QuoteID < 0 {
+		return nil, newValidationError("quote_id", "quote_id cannot be negative")
+	}
+
+	filter := storage.AuditFilter{QuoteID: req.QuoteID, Actor: req.Actor}
+	if req.Since != "" {
+		since, err := parseSince(req.Since)
+		if err != nil {
+			return nil, newValidationError("since", "since must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		filter.Since = since
+	}
+
+	events, err := s.DB.GetAuditEvents(ctx, filter, pageLimit(req.Limit), req.Offset)
+	if err != nil {
+		s.Log.Error("failed to get audit events", "error", err)
+		return nil, fmt.Errorf("failed to get audit events: %w", err)
+	}
+
+	result := make([]AuditEvent, 0, len(events))
+	for _, e := range events {
+		result = append(result, toAppAuditEvent(e))
+	}
+
+	return &GetAuditEventsResponse{Events: result}, nil
+}
+
+// parseSince accepts an RFC 3339 timestamp or a date, taken as midnight UTC.
+func parseSince(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(dateLayout, raw)
+}
@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrUnauthorized is returned for a missing, malformed, expired or wrongly signed token.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string
+	Role    Role
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored by WithPrincipal, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+}
+
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	Role      Role   `json:"role"`
+	ExpiresAt *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+}
+
+// Authenticate verifies an HS256-signed JWT against Config.Secret. The token
+// must carry a subject and, when present, exp and nbf must hold at the current time.
+// The role claim defaults to reader. Without a configured secret every token is rejected.
+func (s *Service) Authenticate(ctx context.Context, req *AuthenticateRequest) (*AuthenticateResponse, error) {
+	if s.Config == nil || s.Config.Secret == "" {
+		return nil, ErrUnauthorized
+	}
+
+	claims, err := verifyToken(req.Token, []byte(s.Config.Secret), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	role := claims.Role
+	if role == "" {
+		role = RoleReader
+	}
+	if !role.valid() {
+		return nil, ErrUnauthorized
+	}
+
+	return &AuthenticateResponse{Principal: Principal{Subject: claims.Subject, Role: role}}, nil
+}
+
+func verifyToken(token string, secret []byte, now time.Time) (*tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrUnauthorized
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return nil, ErrUnauthorized
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrUnauthorized
+	}
+
+	var claims tokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" {
+		return nil, ErrUnauthorized
+	}
+	if claims.ExpiresAt != nil && now.Unix() >= *claims.ExpiresAt {
+		return nil, ErrUnauthorized
+	}
+	if claims.NotBefore != nil && now.Unix() < *claims.NotBefore {
+		return nil, ErrUnauthorized
+	}
+	return &claims, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
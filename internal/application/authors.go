@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+)
+
+func toAppAuthor(sa *storage.Author) Author {
+	return Author{
+		ID:        sa.ID,
+		Name:      sa.Name,
+		Aliases:   sa.Aliases,
+		Bio:       sa.Bio,
+		BirthYear: sa.BirthYear,
+		DeathYear: sa.DeathYear,
+	}
+}
+
+func (s *Service) GetAuthors(ctx context.Context, req *GetAuthorsRequest) (*GetAuthorsResponse, error) {
+	if req.Offset < 0 {
+		return nil, newValidationError("offset", "offset cannot be negative")
+	}
+
+	authors, err := s.DB.GetAuthors(ctx, pageLimit(req.Limit), req.Offset)
+	if err != nil {
+		s.Log.Error("failed to get authors", "error", err)
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+
+	result := make([]Author, 0, len(authors))
+	for _, a := range authors {
+		result = append(result, toAppAuthor(a))
+	}
+
+	return &GetAuthorsResponse{Authors: result}, nil
+}
+
+func (s *Service) GetAuthor(ctx context.Context, req *GetAuthorRequest) (*GetAuthorResponse, error) {
+	if req.ID == 0 {
+		return nil, newValidationError("id", "id parameter is required")
+	}
+
+	author, err := s.DB.GetAuthorByID(ctx, req.ID)
+	if err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to get author", "id", req.ID, "error", err)
+		}
+		return nil, storageError("failed to get author", err)
+	}
+
+	return &GetAuthorResponse{Author: toAppAuthor(author)}, nil
+}
+
+// GetAuthorQuotes lists the quotes of an existing author; an unknown author is ErrNotFound
+// rather than an empty page.
+func (s *Service) GetAuthorQuotes(ctx context.Context, req *GetAuthorQuotesRequest) (*GetAuthorQuotesResponse, error) {
+	if req.ID == 0 {
+		return nil, newValidationError("id", "id parameter is required")
+	}
+
+	page, err := toPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.DB.GetAuthorByID(ctx, req.ID); err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to get author", "id", req.ID, "error", err)
+		}
+		return nil, storageError("failed to get author", err)
+	}
+
+	quotes, next, err := s.DB.GetAllQuotes(ctx, storage.QuoteFilter{AuthorID: req.ID}, page)
+	if err != nil {
+		s.Log.Error("failed to get author quotes", "id", req.ID, "error", err)
+		return nil, fmt.Errorf("failed to get author quotes: %w", err)
+	}
+
+	result := make([]Quote, 0, len(quotes))
+	for _, q := range quotes {
+		result = append(result, publicQuote(ctx, q))
+	}
+
+	return &GetAuthorQuotesResponse{Quotes: result, NextCursor: encodeCursor(next)}, nil
+}
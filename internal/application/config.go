@@ -1,6 +1,15 @@
 package application
 
+import "time"
+
 type Config struct {
 	Name   string `env:"NAME" envDefault:"labels-api" yaml:"name"`
 	Secret string `env:"SECRET" yaml:"secret"`
+	// DailyTimezone is the IANA zone whose calendar days the quote of the day follows.
+	DailyTimezone string `env:"DAILY_TIMEZONE" envDefault:"UTC" yaml:"daily-timezone"`
+	// TrashRetention is how long deleted quotes stay restorable before the purge job
+	// removes them. It must be positive unless PurgeInterval is zero.
+	TrashRetention time.Duration `env:"TRASH_RETENTION" envDefault:"720h" yaml:"trash-retention"`
+	// PurgeInterval is how often the purge job runs; zero disables it.
+	PurgeInterval time.Duration `env:"PURGE_INTERVAL" envDefault:"1h" yaml:"purge-interval"`
 }
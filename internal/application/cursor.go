@@ -0,0 +1,70 @@
+package application
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// ErrInvalidCursor is returned for a cursor that was not issued by this service.
+var ErrInvalidCursor error = &ValidationError{Field: "cursor", Message: "invalid cursor"}
+
+// encodeCursor turns a storage position into the opaque token handed out to clients.
+func encodeCursor(c *storage.Cursor) string {
+	if c == nil {
+		return ""
+	}
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(token string) (*storage.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	quoteID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &storage.Cursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: quoteID}, nil
+}
+
+// toPage decodes the cursor and normalizes the requested page size.
+func toPage(limit int, cursor string) (storage.Page, error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return storage.Page{}, err
+	}
+	return storage.Page{Limit: pageLimit(limit), After: after}, nil
+}
+
+// pageLimit applies the default and maximum page size to a requested limit.
+func pageLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return defaultPageLimit
+	case limit > maxPageLimit:
+		return maxPageLimit
+	}
+	return limit
+}
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// dailyLocation is the timezone of the quote-of-the-day calendar, UTC unless configured.
+func (s *Service) dailyLocation() *time.Location {
+	if s.location == nil {
+		return time.UTC
+	}
+	return s.location
+}
+
+// GetDailyQuote returns the quote of the day. Today's quote is selected on the
+// first request of the day; past days are only looked up, so a day that had no
+// request is ErrNotFound.
+func (s *Service) GetDailyQuote(ctx context.Context, req *GetDailyQuoteRequest) (*GetDailyQuoteResponse, error) {
+	loc := s.dailyLocation()
+	today := time.Now().In(loc).Format(dateLayout)
+
+	date := today
+	if req.Date != "" {
+		day, err := time.ParseInLocation(dateLayout, req.Date, loc)
+		if err != nil {
+			return nil, newValidationError("date", "date must be in YYYY-MM-DD format")
+		}
+		date = day.Format(dateLayout)
+		if date > today {
+			return nil, newValidationError("date", "date cannot be in the future")
+		}
+	}
+	day, _ := time.ParseInLocation(dateLayout, date, loc)
+
+	lookup := s.DB.GetDailyQuote
+	if date == today {
+		lookup = s.DB.PickDailyQuote
+	}
+	quote, err := lookup(ctx, day)
+	if err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to get daily quote", "date", date, "error", err)
+		}
+		return nil, storageError("failed to get daily quote", err)
+	}
+
+	return &GetDailyQuoteResponse{Date: date, Quote: publicQuote(ctx, quote)}, nil
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+)
+
+// Sentinel errors returned by QuoteService. Transports map them onto their own
+// status codes, so callers should match them with errors.Is.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+	// ErrPreconditionFailed is returned when a conditional write names a version the quote no longer has.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// ValidationError reports a rejected request field. It matches ErrValidation.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func newValidationError(field, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
+
+// DuplicateQuoteError reports that the added quote is already stored under
+// ExistingID. It matches ErrConflict.
+type DuplicateQuoteError struct {
+	ExistingID int64
+}
+
+func (e *DuplicateQuoteError) Error() string {
+	return fmt.Sprintf("quote already exists with id %d", e.ExistingID)
+}
+
+func (e *DuplicateQuoteError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// storageError translates storage sentinels into application errors and
+// prefixes anything else with the failed operation.
+func storageError(op string, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return fmt.Errorf("%s: %w", op, ErrNotFound)
+	case errors.Is(err, storage.ErrConflict):
+		return fmt.Errorf("%s: %w", op, ErrConflict)
+	case errors.Is(err, storage.ErrVersionMismatch):
+		return fmt.Errorf("%s: %w", op, ErrPreconditionFailed)
+	case errors.Is(err, storage.ErrNotOwner):
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// isExpected tells whether err is a client-side outcome that does not need to be logged as a failure.
+func isExpected(err error) bool {
+	return errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrConflict) ||
+		errors.Is(err, storage.ErrVersionMismatch) || errors.Is(err, storage.ErrNotOwner)
+}
@@ -0,0 +1,189 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+)
+
+// ExportFormat selects how ExportQuotes encodes quotes.
+type ExportFormat string
+
+const (
+	// ExportJSON is a JSON array of quotes.
+	ExportJSON ExportFormat = "json"
+	// ExportNDJSON is one JSON quote per line.
+	ExportNDJSON ExportFormat = "ndjson"
+	// ExportCSV has a header row and uses the columns ImportCSV reads.
+	ExportCSV ExportFormat = "csv"
+	// ExportMarkdown renders every quote as a blockquote.
+	ExportMarkdown ExportFormat = "markdown"
+)
+
+// exportColumns is the header of a CSV export.
+var exportColumns = []string{"id", "author", "quote", "lang", "tags", "created_by", "created_at", "updated_at"}
+
+// ExportQuotes writes every quote matching the request filters to req.Out as it
+// is read from storage. Validation errors, and storage errors raised before the
+// first quote, are returned before anything is written.
+func (s *Service) ExportQuotes(ctx context.Context, req *ExportQuotesRequest) error {
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return err
+	}
+	enc, err := newExportEncoder(req.Format, req.Out)
+	if err != nil {
+		return err
+	}
+
+	// The preamble is written with the first quote, so a storage error raised
+	// before any row is read leaves req.Out untouched.
+	begun := false
+	begin := func() error {
+		if begun {
+			return nil
+		}
+		begun = true
+		return enc.begin()
+	}
+	err = s.DB.ExportQuotes(ctx, storage.QuoteFilter{
+		Author:       strings.TrimSpace(req.Author),
+		Tags:         tags,
+		MatchAllTags: req.MatchAllTags,
+	}, func(q *storage.Quote) error {
+		if err := begin(); err != nil {
+			return err
+		}
+		return enc.encode(publicQuote(ctx, q))
+	})
+	if err != nil {
+		s.Log.Error("failed to export quotes", "error", err)
+		return fmt.Errorf("failed to export quotes: %w", err)
+	}
+	if err := begin(); err != nil {
+		return fmt.Errorf("failed to export quotes: %w", err)
+	}
+	if err := enc.end(); err != nil {
+		return fmt.Errorf("failed to export quotes: %w", err)
+	}
+	return nil
+}
+
+// exportEncoder writes quotes one by one in an export format.
+type exportEncoder interface {
+	begin() error
+	encode(q Quote) error
+	end() error
+}
+
+func newExportEncoder(format ExportFormat, w io.Writer) (exportEncoder, error) {
+	switch format {
+	case ExportJSON:
+		return &jsonExportEncoder{w: w}, nil
+	case ExportNDJSON:
+		return &ndjsonExportEncoder{enc: json.NewEncoder(w)}, nil
+	case ExportCSV:
+		return &csvExportEncoder{w: csv.NewWriter(w)}, nil
+	case ExportMarkdown:
+		return &markdownExportEncoder{w: w}, nil
+	}
+	return nil, newValidationError("format", fmt.Sprintf("unsupported export format %q", format))
+}
+
+type jsonExportEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonExportEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExportEncoder) encode(q Quote) error {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "\n"
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExportEncoder) end() error {
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+type ndjsonExportEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonExportEncoder) begin() error { return nil }
+
+func (e *ndjsonExportEncoder) encode(q Quote) error { return e.enc.Encode(q) }
+
+func (e *ndjsonExportEncoder) end() error { return nil }
+
+type csvExportEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvExportEncoder) begin() error {
+	return e.w.Write(exportColumns)
+}
+
+func (e *csvExportEncoder) encode(q Quote) error {
+	return e.w.Write([]string{
+		strconv.FormatInt(q.ID, 10),
+		q.Author,
+		q.Quote,
+		q.Lang,
+		strings.Join(q.Tags, ","),
+		q.CreatedBy,
+		q.CreatedAt.Format(time.RFC3339),
+		q.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (e *csvExportEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type markdownExportEncoder struct {
+	w io.Writer
+}
+
+func (e *markdownExportEncoder) begin() error { return nil }
+
+func (e *markdownExportEncoder) encode(q Quote) error {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(q.Quote), "\n") {
+		b.WriteString(strings.TrimRight("> "+line, " "))
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, ">\n> — %s\n", q.Author)
+	if len(q.Tags) > 0 {
+		fmt.Fprintf(&b, "\n`%s`\n", strings.Join(q.Tags, "` `"))
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *markdownExportEncoder) end() error { return nil }
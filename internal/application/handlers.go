@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/azaliaz/quote-service/internal/storage"
+	"strings"
+	"unicode"
 
 	"time"
 )
@@ -12,34 +14,117 @@ import (
 func toAppQuote(sq *storage.Quote) Quote {
 	return Quote{
 		ID:        sq.ID,
+		AuthorID:  sq.AuthorID,
 		Author:    sq.Author,
 		Quote:     sq.Quote,
+		Lang:      sq.Lang,
+		CreatedBy: sq.CreatedBy,
+		Version:   sq.Version,
 		CreatedAt: sq.CreatedAt,
+		UpdatedAt: sq.UpdatedAt,
+		DeletedAt: sq.DeletedAt,
+		Tags:      sq.Tags,
 	}
 }
 
-func (s *Service) AddQuote(ctx context.Context, req *AddQuoteRequest) (*AddQuoteResponse, error) {
-	if req.Author == "" || req.Quote == "" {
-		return nil, errors.New("author and quote cannot be empty")
+// hasText reports whether a quote keeps any characters once punctuation and
+// whitespace are dropped. quote_content_hash ignores both, so a quote without
+// them would collide with every other such quote.
+func hasText(quote string) bool {
+	return strings.IndexFunc(quote, func(r rune) bool {
+		return !unicode.IsSpace(r) && !unicode.IsPunct(r)
+	}) >= 0
+}
+
+// publicQuote converts sq for a read response, leaving CreatedBy out unless
+// the caller sees owners.
+func publicQuote(ctx context.Context, sq *storage.Quote) Quote {
+	q := toAppQuote(sq)
+	if !seesOwners(ctx) {
+		q.CreatedBy = ""
+	}
+	return q
+}
+
+// newQuote validates an added quote and normalizes its tags and language.
+func newQuote(req *AddQuoteRequest, createdBy string) (*storage.Quote, error) {
+	if req.Author == "" {
+		return nil, newValidationError("author", "author and quote cannot be empty")
+	}
+	if req.Quote == "" {
+		return nil, newValidationError("quote", "author and quote cannot be empty")
+	}
+	if !hasText(req.Quote) {
+		return nil, newValidationError("quote", "quote must contain more than punctuation")
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	lang, err := normalizeLang(req.Lang)
+	if err != nil {
+		return nil, err
 	}
 
-	quote := storage.Quote{
+	return &storage.Quote{
 		Author:    req.Author,
 		Quote:     req.Quote,
+		Lang:      lang,
+		CreatedBy: createdBy,
 		CreatedAt: time.Now(),
+		Tags:      tags,
+	}, nil
+}
+
+func (s *Service) AddQuote(ctx context.Context, req *AddQuoteRequest) (*AddQuoteResponse, error) {
+	principal, err := requireRole(ctx, RoleEditor, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := newQuote(req, principal.Subject)
+	if err != nil {
+		return nil, err
 	}
 
-	id, err := s.DB.AddQuote(ctx, &quote)
+	id, err := s.DB.AddQuote(withActor(ctx, principal.Subject), quote)
+	var dup *storage.DuplicateError
+	if errors.As(err, &dup) {
+		if !req.Upsert {
+			return nil, &DuplicateQuoteError{ExistingID: dup.ID}
+		}
+		existing, err := s.DB.GetQuoteByID(ctx, dup.ID)
+		if err != nil {
+			s.Log.Error("failed to get existing quote", "id", dup.ID, "error", err)
+			return nil, storageError("failed to get existing quote", err)
+		}
+		return &AddQuoteResponse{ID: existing.ID, Version: existing.Version}, nil
+	}
 	if err != nil {
-		s.Log.Error("failed to add quote", "error", err)
-		return nil, fmt.Errorf("failed to add quote: %w", err)
+		if !isExpected(err) {
+			s.Log.Error("failed to add quote", "error", err)
+		}
+		return nil, storageError("failed to add quote", err)
 	}
 
-	return &AddQuoteResponse{ID: id}, nil
+	// A new quote starts at version 1.
+	return &AddQuoteResponse{ID: id, Created: true, Version: 1}, nil
 }
 
 func (s *Service) GetQuotes(ctx context.Context, req *GetQuotesRequest) (*GetQuotesResponse, error) {
-	quotes, err := s.DB.GetAllQuotes(ctx)
+	page, err := toPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, next, err := s.DB.GetAllQuotes(ctx, storage.QuoteFilter{
+		Author:       req.Author,
+		Tags:         tags,
+		MatchAllTags: req.MatchAllTags,
+	}, page)
 	if err != nil {
 		s.Log.Error("failed to get quotes", "error", err)
 		return nil, fmt.Errorf("failed to get quotes: %w", err)
@@ -48,33 +133,65 @@ func (s *Service) GetQuotes(ctx context.Context, req *GetQuotesRequest) (*GetQuo
 	result := make([]Quote, 0, len(quotes))
 
 	for _, q := range quotes {
-		result = append(result, Quote{
-			ID:        q.ID,
-			Author:    q.Author,
-			Quote:     q.Quote,
-			CreatedAt: q.CreatedAt,
-		})
+		result = append(result, publicQuote(ctx, q))
 	}
 
-	return &GetQuotesResponse{Quotes: result}, nil
+	return &GetQuotesResponse{Quotes: result, NextCursor: encodeCursor(next)}, nil
 }
 
 func (s *Service) GetRandomQuote(ctx context.Context, req *GetRandomQuoteRequest) (*GetRandomQuoteResponse, error) {
-	storageQuote, err := s.DB.GetRandomQuote(ctx)
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	lang, err := normalizeLang(req.Lang)
+	if err != nil {
+		return nil, err
+	}
+
+	storageQuote, err := s.DB.GetRandomQuote(ctx, storage.QuoteFilter{
+		Author:       strings.TrimSpace(req.Author),
+		Tags:         tags,
+		MatchAllTags: req.MatchAllTags,
+		Lang:         lang,
+	})
+	if err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to get random quote", "error", err)
+		}
+		return nil, storageError("failed to get random quote", err)
+	}
+
+	return &GetRandomQuoteResponse{Quote: publicQuote(ctx, storageQuote)}, nil
+}
+
+func (s *Service) GetQuote(ctx context.Context, req *GetQuoteRequest) (*GetQuoteResponse, error) {
+	if req.ID == 0 {
+		return nil, newValidationError("id", "id parameter is required")
+	}
+
+	storageQuote, err := s.DB.GetQuoteByID(ctx, req.ID)
 	if err != nil {
-		s.Log.Error("failed to get random quote", "error", err)
-		return nil, fmt.Errorf("failed to get random quote: %w", err)
+		if !isExpected(err) {
+			s.Log.Error("failed to get quote", "id", req.ID, "error", err)
+		}
+		return nil, storageError("failed to get quote", err)
 	}
 
-	return &GetRandomQuoteResponse{Quote: toAppQuote(storageQuote)}, nil
+	return &GetQuoteResponse{Quote: publicQuote(ctx, storageQuote)}, nil
 }
 
 func (s *Service) GetQuotesByAuthor(ctx context.Context, req *GetQuotesByAuthorRequest) (*GetQuotesByAuthorResponse, error) {
 	if req.Author == "" {
-		return nil, errors.New("author parameter is required")
+		return nil, newValidationError("author", "author parameter is required")
 	}
 
-	quotes, err := s.DB.GetQuotesByAuthor(ctx, req.Author)
+	page, err := toPage(req.Limit, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, next, err := s.DB.GetQuotesByAuthor(ctx, req.Author, page)
 	if err != nil {
 		s.Log.Error("failed to get quotes by author", "author", req.Author, "error", err)
 		return nil, fmt.Errorf("failed to get quotes by author: %w", err)
@@ -82,22 +199,113 @@ func (s *Service) GetQuotesByAuthor(ctx context.Context, req *GetQuotesByAuthorR
 
 	result := make([]Quote, 0, len(quotes))
 	for _, q := range quotes {
-		result = append(result, toAppQuote(q))
+		result = append(result, publicQuote(ctx, q))
+	}
+
+	return &GetQuotesByAuthorResponse{Quotes: result, NextCursor: encodeCursor(next)}, nil
+}
+
+func (s *Service) UpdateQuote(ctx context.Context, req *UpdateQuoteRequest) (*UpdateQuoteResponse, error) {
+	principal, err := requireRole(ctx, RoleEditor, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.ID == 0 {
+		return nil, newValidationError("id", "id parameter is required")
+	}
+	if req.Replace && req.Author == nil {
+		return nil, newValidationError("author", "author and quote are required")
+	}
+	if req.Replace && req.Quote == nil {
+		return nil, newValidationError("quote", "author and quote are required")
+	}
+	if req.Author == nil && req.Quote == nil {
+		return nil, newValidationError("", "nothing to update")
+	}
+	if req.Author != nil && *req.Author == "" {
+		return nil, newValidationError("author", "author and quote cannot be empty")
+	}
+	if req.Quote != nil && *req.Quote == "" {
+		return nil, newValidationError("quote", "author and quote cannot be empty")
+	}
+	if req.Quote != nil && !hasText(*req.Quote) {
+		return nil, newValidationError("quote", "quote must contain more than punctuation")
 	}
 
-	return &GetQuotesByAuthorResponse{Quotes: result}, nil
+	updated, err := s.DB.UpdateQuote(withActor(ctx, principal.Subject), req.ID, &storage.QuoteUpdate{
+		Author:   req.Author,
+		Quote:    req.Quote,
+		Versions: req.IfVersions,
+	})
+	if err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to update quote", "id", req.ID, "error", err)
+		}
+		return nil, storageError("failed to update quote", err)
+	}
+
+	return &UpdateQuoteResponse{Quote: toAppQuote(updated)}, nil
 }
 
+// DeleteQuote moves a quote to the trash. Admins may delete any quote, editors only the ones they added.
 func (s *Service) DeleteQuote(ctx context.Context, req *DeleteQuoteRequest) (*DeleteQuoteResponse, error) {
+	principal, err := requireRole(ctx, RoleEditor, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
 	if req.ID == 0 {
-		return nil, errors.New("id parameter is required")
+		return nil, newValidationError("id", "id parameter is required")
 	}
 
-	err := s.DB.DeleteQuote(ctx, req.ID)
+	err = s.DB.DeleteQuote(withActor(ctx, principal.Subject), req.ID, ownerOf(principal), req.IfVersions)
 	if err != nil {
-		s.Log.Error("failed to delete quote", "id", req.ID, "error", err)
-		return &DeleteQuoteResponse{Success: false}, fmt.Errorf("failed to delete quote: %w", err)
+		if !isExpected(err) {
+			s.Log.Error("failed to delete quote", "id", req.ID, "error", err)
+		}
+		return nil, storageError("failed to delete quote", err)
 	}
 
 	return &DeleteQuoteResponse{Success: true}, nil
 }
+
+func (s *Service) SearchQuotes(ctx context.Context, req *SearchQuotesRequest) (*SearchQuotesResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, newValidationError("q", "query parameter is required")
+	}
+	if req.Offset < 0 {
+		return nil, newValidationError("offset", "offset cannot be negative")
+	}
+
+	found, err := s.DB.SearchQuotes(ctx, query, pageLimit(req.Limit), req.Offset)
+	if err != nil {
+		s.Log.Error("failed to search quotes", "query", query, "error", err)
+		return nil, fmt.Errorf("failed to search quotes: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(found))
+	for _, r := range found {
+		results = append(results, SearchResult{
+			Quote:    publicQuote(ctx, &r.Quote),
+			Rank:     r.Rank,
+			Headline: r.Headline,
+		})
+	}
+
+	return &SearchQuotesResponse{Results: results}, nil
+}
+
+func (s *Service) GetTags(ctx context.Context, req *GetTagsRequest) (*GetTagsResponse, error) {
+	tags, err := s.DB.GetTags(ctx)
+	if err != nil {
+		s.Log.Error("failed to get tags", "error", err)
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	result := make([]Tag, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, Tag{Name: t.Name, Count: t.Count})
+	}
+
+	return &GetTagsResponse{Tags: result}, nil
+}
@@ -0,0 +1,277 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+)
+
+// ImportFormat selects how ImportQuotesRequest.Body is decoded.
+type ImportFormat string
+
+const (
+	// ImportJSON is a JSON array of objects shaped like AddQuoteRequest.
+	ImportJSON ImportFormat = "json"
+	// ImportNDJSON is one such object per line.
+	ImportNDJSON ImportFormat = "ndjson"
+	// ImportCSV has a header row naming the author and quote columns and
+	// optionally tags (comma-separated) and lang.
+	ImportCSV ImportFormat = "csv"
+)
+
+// Statuses of an ImportRow.
+const (
+	ImportInserted  = "inserted"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+)
+
+// importBatchSize is how many valid records are stored per transaction.
+const importBatchSize = 500
+
+// maxImportLineSize bounds a single NDJSON line.
+const maxImportLineSize = 1 << 20
+
+// ImportQuotes adds every record of req.Body. Records are validated like in
+// AddQuote and stored in batches of importBatchSize, each in its own transaction.
+// Duplicates and invalid records are reported per row instead of failing the import.
+// A dry run reports the same outcomes as the real import would.
+// A record the decoder cannot get past ends the import; it is reported as invalid.
+func (s *Service) ImportQuotes(ctx context.Context, req *ImportQuotesRequest) (*ImportQuotesResponse, error) {
+	principal, err := requireRole(ctx, RoleEditor, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	records, err := newImportReader(req.Format, req.Body)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withActor(ctx, principal.Subject)
+
+	resp := &ImportQuotesResponse{DryRun: req.DryRun, Rows: []ImportRow{}}
+	var (
+		batch     []*storage.Quote
+		batchRows []int
+		// seen holds the texts a dry run pretended to insert: its batches are
+		// rolled back, so later batches would not find them in storage.
+		seen = make(map[string]bool)
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		outcomes, err := s.DB.ImportQuotes(ctx, batch, req.DryRun)
+		if err != nil {
+			s.Log.Error("failed to import quotes", "error", err)
+			return fmt.Errorf("failed to import quotes: %w", err)
+		}
+		for i, outcome := range outcomes {
+			row := &resp.Rows[batchRows[i]]
+			row.ID = outcome.ID
+			if outcome.Duplicate || req.DryRun && seen[outcome.ContentHash] {
+				row.Status = ImportDuplicate
+				resp.Duplicates++
+				continue
+			}
+			row.Status = ImportInserted
+			resp.Inserted++
+			if req.DryRun {
+				seen[outcome.ContentHash] = true
+			}
+		}
+		batch, batchRows = batch[:0], batchRows[:0]
+		return nil
+	}
+
+	for row := 1; ; row++ {
+		record, err := records.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			resp.Rows = append(resp.Rows, ImportRow{Row: row, Status: ImportInvalid, Error: err.Error()})
+			resp.Invalid++
+			var recErr *recordError
+			if errors.As(err, &recErr) {
+				continue
+			}
+			break
+		}
+
+		quote, err := newQuote(record, principal.Subject)
+		if err != nil {
+			resp.Rows = append(resp.Rows, ImportRow{Row: row, Status: ImportInvalid, Error: err.Error()})
+			resp.Invalid++
+			continue
+		}
+		resp.Rows = append(resp.Rows, ImportRow{Row: row})
+		batch = append(batch, quote)
+		batchRows = append(batchRows, len(resp.Rows)-1)
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// recordError rejects a single record; the records after it can still be read.
+type recordError struct {
+	err error
+}
+
+func (e *recordError) Error() string {
+	return e.err.Error()
+}
+
+func (e *recordError) Unwrap() error {
+	return e.err
+}
+
+// importReader yields import records one at a time and io.EOF after the last one.
+type importReader interface {
+	next() (*AddQuoteRequest, error)
+}
+
+// newImportReader checks the start of body (the opening bracket of a JSON
+// array, the CSV header) and returns a reader for its records.
+func newImportReader(format ImportFormat, body io.Reader) (importReader, error) {
+	switch format {
+	case ImportJSON:
+		dec := json.NewDecoder(body)
+		tok, err := dec.Token()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, newValidationError("body", "body must be a JSON array")
+		}
+		if delim, ok := tok.(json.Delim); err == nil && (!ok || delim != '[') {
+			return nil, newValidationError("body", "body must be a JSON array")
+		}
+		return &jsonImportReader{dec: dec, done: errors.Is(err, io.EOF)}, nil
+	case ImportNDJSON:
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+		return &ndjsonImportReader{scanner: scanner}, nil
+	case ImportCSV:
+		return newCSVImportReader(body)
+	}
+	return nil, newValidationError("format", fmt.Sprintf("unsupported import format %q", format))
+}
+
+type jsonImportReader struct {
+	dec  *json.Decoder
+	done bool
+}
+
+func (r *jsonImportReader) next() (*AddQuoteRequest, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	if !r.dec.More() {
+		r.done = true
+		if _, err := r.dec.Token(); err != nil {
+			return nil, fmt.Errorf("malformed JSON: %w", err)
+		}
+		return nil, io.EOF
+	}
+
+	var req AddQuoteRequest
+	if err := r.dec.Decode(&req); err != nil {
+		// A value of the wrong type has been consumed whole, so the array can be read on.
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, &recordError{err: fmt.Errorf("invalid record: %w", err)}
+		}
+		return nil, fmt.Errorf("malformed JSON: %w", err)
+	}
+	return &req, nil
+}
+
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *ndjsonImportReader) next() (*AddQuoteRequest, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+		var req AddQuoteRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			return nil, &recordError{err: fmt.Errorf("invalid record: %w", err)}
+		}
+		return &req, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("malformed NDJSON: %w", err)
+	}
+	return nil, io.EOF
+}
+
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVImportReader(body io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(body)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, newValidationError("body", "CSV body must start with a header row")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"author", "quote"} {
+		if _, ok := columns[required]; !ok {
+			return nil, newValidationError("body", "CSV header must name the author and quote columns")
+		}
+	}
+	reader.FieldsPerRecord = len(header)
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvImportReader) next() (*AddQuoteRequest, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if errors.Is(err, csv.ErrFieldCount) {
+		return nil, &recordError{err: fmt.Errorf("invalid record: %w", err)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("malformed CSV: %w", err)
+	}
+
+	req := &AddQuoteRequest{
+		Author: r.field(record, "author"),
+		Quote:  r.field(record, "quote"),
+		Lang:   r.field(record, "lang"),
+	}
+	if tags := r.field(record, "tags"); tags != "" {
+		req.Tags = strings.Split(tags, ",")
+	}
+	return req, nil
+}
+
+func (r *csvImportReader) field(record []string, name string) string {
+	i, ok := r.columns[name]
+	if !ok {
+		return ""
+	}
+	return record[i]
+}
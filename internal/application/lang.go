@@ -0,0 +1,21 @@
+package application
+
+import "strings"
+
+// normalizeLang lower-cases a language code and checks that it looks like an
+// ISO 639-1 or 639-3 code ("en", "ru", "grc"). An empty code means unknown.
+func normalizeLang(lang string) (string, error) {
+	code := strings.ToLower(strings.TrimSpace(lang))
+	if code == "" {
+		return "", nil
+	}
+	if len(code) < 2 || len(code) > 3 {
+		return "", newValidationError("lang", "lang must be a 2 or 3 letter language code")
+	}
+	for _, r := range code {
+		if r < 'a' || r > 'z' {
+			return "", newValidationError("lang", "lang must be a 2 or 3 letter language code")
+		}
+	}
+	return code, nil
+}
@@ -50,6 +50,21 @@ func (mr *MockQuoteServiceMockRecorder) AddQuote(ctx, req interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddQuote", reflect.TypeOf((*MockQuoteService)(nil).AddQuote), ctx, req)
 }
 
+// Authenticate mocks base method.
+func (m *MockQuoteService) Authenticate(ctx context.Context, req *application.AuthenticateRequest) (*application.AuthenticateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, req)
+	ret0, _ := ret[0].(*application.AuthenticateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockQuoteServiceMockRecorder) Authenticate(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockQuoteService)(nil).Authenticate), ctx, req)
+}
+
 // DeleteQuote mocks base method.
 func (m *MockQuoteService) DeleteQuote(ctx context.Context, req *application.DeleteQuoteRequest) (*application.DeleteQuoteResponse, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +80,140 @@ func (mr *MockQuoteServiceMockRecorder) DeleteQuote(ctx, req interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQuote", reflect.TypeOf((*MockQuoteService)(nil).DeleteQuote), ctx, req)
 }
 
+// DiffQuoteRevisions mocks base method.
+func (m *MockQuoteService) DiffQuoteRevisions(ctx context.Context, req *application.DiffQuoteRevisionsRequest) (*application.DiffQuoteRevisionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffQuoteRevisions", ctx, req)
+	ret0, _ := ret[0].(*application.DiffQuoteRevisionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffQuoteRevisions indicates an expected call of DiffQuoteRevisions.
+func (mr *MockQuoteServiceMockRecorder) DiffQuoteRevisions(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffQuoteRevisions", reflect.TypeOf((*MockQuoteService)(nil).DiffQuoteRevisions), ctx, req)
+}
+
+// ExportQuotes mocks base method.
+func (m *MockQuoteService) ExportQuotes(ctx context.Context, req *application.ExportQuotesRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportQuotes", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportQuotes indicates an expected call of ExportQuotes.
+func (mr *MockQuoteServiceMockRecorder) ExportQuotes(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportQuotes", reflect.TypeOf((*MockQuoteService)(nil).ExportQuotes), ctx, req)
+}
+
+// GetAuditEvents mocks base method.
+func (m *MockQuoteService) GetAuditEvents(ctx context.Context, req *application.GetAuditEventsRequest) (*application.GetAuditEventsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEvents", ctx, req)
+	ret0, _ := ret[0].(*application.GetAuditEventsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEvents indicates an expected call of GetAuditEvents.
+func (mr *MockQuoteServiceMockRecorder) GetAuditEvents(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEvents", reflect.TypeOf((*MockQuoteService)(nil).GetAuditEvents), ctx, req)
+}
+
+// GetAuthor mocks base method.
+func (m *MockQuoteService) GetAuthor(ctx context.Context, req *application.GetAuthorRequest) (*application.GetAuthorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthor", ctx, req)
+	ret0, _ := ret[0].(*application.GetAuthorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthor indicates an expected call of GetAuthor.
+func (mr *MockQuoteServiceMockRecorder) GetAuthor(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthor", reflect.TypeOf((*MockQuoteService)(nil).GetAuthor), ctx, req)
+}
+
+// GetAuthorQuotes mocks base method.
+func (m *MockQuoteService) GetAuthorQuotes(ctx context.Context, req *application.GetAuthorQuotesRequest) (*application.GetAuthorQuotesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorQuotes", ctx, req)
+	ret0, _ := ret[0].(*application.GetAuthorQuotesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorQuotes indicates an expected call of GetAuthorQuotes.
+func (mr *MockQuoteServiceMockRecorder) GetAuthorQuotes(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorQuotes", reflect.TypeOf((*MockQuoteService)(nil).GetAuthorQuotes), ctx, req)
+}
+
+// GetAuthors mocks base method.
+func (m *MockQuoteService) GetAuthors(ctx context.Context, req *application.GetAuthorsRequest) (*application.GetAuthorsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthors", ctx, req)
+	ret0, _ := ret[0].(*application.GetAuthorsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthors indicates an expected call of GetAuthors.
+func (mr *MockQuoteServiceMockRecorder) GetAuthors(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthors", reflect.TypeOf((*MockQuoteService)(nil).GetAuthors), ctx, req)
+}
+
+// GetDailyQuote mocks base method.
+func (m *MockQuoteService) GetDailyQuote(ctx context.Context, req *application.GetDailyQuoteRequest) (*application.GetDailyQuoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyQuote", ctx, req)
+	ret0, _ := ret[0].(*application.GetDailyQuoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyQuote indicates an expected call of GetDailyQuote.
+func (mr *MockQuoteServiceMockRecorder) GetDailyQuote(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyQuote", reflect.TypeOf((*MockQuoteService)(nil).GetDailyQuote), ctx, req)
+}
+
+// GetQuote mocks base method.
+func (m *MockQuoteService) GetQuote(ctx context.Context, req *application.GetQuoteRequest) (*application.GetQuoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuote", ctx, req)
+	ret0, _ := ret[0].(*application.GetQuoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuote indicates an expected call of GetQuote.
+func (mr *MockQuoteServiceMockRecorder) GetQuote(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuote", reflect.TypeOf((*MockQuoteService)(nil).GetQuote), ctx, req)
+}
+
+// GetQuoteRevisions mocks base method.
+func (m *MockQuoteService) GetQuoteRevisions(ctx context.Context, req *application.GetQuoteRevisionsRequest) (*application.GetQuoteRevisionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuoteRevisions", ctx, req)
+	ret0, _ := ret[0].(*application.GetQuoteRevisionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuoteRevisions indicates an expected call of GetQuoteRevisions.
+func (mr *MockQuoteServiceMockRecorder) GetQuoteRevisions(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuoteRevisions", reflect.TypeOf((*MockQuoteService)(nil).GetQuoteRevisions), ctx, req)
+}
+
 // GetQuotes mocks base method.
 func (m *MockQuoteService) GetQuotes(ctx context.Context, req *application.GetQuotesRequest) (*application.GetQuotesResponse, error) {
 	m.ctrl.T.Helper()
@@ -109,3 +258,108 @@ func (mr *MockQuoteServiceMockRecorder) GetRandomQuote(ctx, req interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRandomQuote", reflect.TypeOf((*MockQuoteService)(nil).GetRandomQuote), ctx, req)
 }
+
+// GetTags mocks base method.
+func (m *MockQuoteService) GetTags(ctx context.Context, req *application.GetTagsRequest) (*application.GetTagsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx, req)
+	ret0, _ := ret[0].(*application.GetTagsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockQuoteServiceMockRecorder) GetTags(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockQuoteService)(nil).GetTags), ctx, req)
+}
+
+// GetTrash mocks base method.
+func (m *MockQuoteService) GetTrash(ctx context.Context, req *application.GetTrashRequest) (*application.GetTrashResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, req)
+	ret0, _ := ret[0].(*application.GetTrashResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockQuoteServiceMockRecorder) GetTrash(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockQuoteService)(nil).GetTrash), ctx, req)
+}
+
+// ImportQuotes mocks base method.
+func (m *MockQuoteService) ImportQuotes(ctx context.Context, req *application.ImportQuotesRequest) (*application.ImportQuotesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportQuotes", ctx, req)
+	ret0, _ := ret[0].(*application.ImportQuotesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportQuotes indicates an expected call of ImportQuotes.
+func (mr *MockQuoteServiceMockRecorder) ImportQuotes(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportQuotes", reflect.TypeOf((*MockQuoteService)(nil).ImportQuotes), ctx, req)
+}
+
+// RestoreQuote mocks base method.
+func (m *MockQuoteService) RestoreQuote(ctx context.Context, req *application.RestoreQuoteRequest) (*application.RestoreQuoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreQuote", ctx, req)
+	ret0, _ := ret[0].(*application.RestoreQuoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreQuote indicates an expected call of RestoreQuote.
+func (mr *MockQuoteServiceMockRecorder) RestoreQuote(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreQuote", reflect.TypeOf((*MockQuoteService)(nil).RestoreQuote), ctx, req)
+}
+
+// RestoreQuoteRevision mocks base method.
+func (m *MockQuoteService) RestoreQuoteRevision(ctx context.Context, req *application.RestoreQuoteRevisionRequest) (*application.RestoreQuoteRevisionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreQuoteRevision", ctx, req)
+	ret0, _ := ret[0].(*application.RestoreQuoteRevisionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreQuoteRevision indicates an expected call of RestoreQuoteRevision.
+func (mr *MockQuoteServiceMockRecorder) RestoreQuoteRevision(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreQuoteRevision", reflect.TypeOf((*MockQuoteService)(nil).RestoreQuoteRevision), ctx, req)
+}
+
+// SearchQuotes mocks base method.
+func (m *MockQuoteService) SearchQuotes(ctx context.Context, req *application.SearchQuotesRequest) (*application.SearchQuotesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchQuotes", ctx, req)
+	ret0, _ := ret[0].(*application.SearchQuotesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchQuotes indicates an expected call of SearchQuotes.
+func (mr *MockQuoteServiceMockRecorder) SearchQuotes(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchQuotes", reflect.TypeOf((*MockQuoteService)(nil).SearchQuotes), ctx, req)
+}
+
+// UpdateQuote mocks base method.
+func (m *MockQuoteService) UpdateQuote(ctx context.Context, req *application.UpdateQuoteRequest) (*application.UpdateQuoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQuote", ctx, req)
+	ret0, _ := ret[0].(*application.UpdateQuoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateQuote indicates an expected call of UpdateQuote.
+func (mr *MockQuoteServiceMockRecorder) UpdateQuote(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuote", reflect.TypeOf((*MockQuoteService)(nil).UpdateQuote), ctx, req)
+}
@@ -0,0 +1,234 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+)
+
+// maxDiffCells bounds the table of the word-by-word comparison of two quote
+// texts, built only for the part between their common start and end. A longer
+// changed part is reported as a single delete and insert.
+const maxDiffCells = 1 << 14
+
+func toAppQuoteRevision(sr *storage.QuoteRevision) QuoteRevision {
+	return QuoteRevision{
+		Revision:  sr.Revision,
+		AuthorID:  sr.AuthorID,
+		Author:    sr.Author,
+		Quote:     sr.Quote,
+		Lang:      sr.Lang,
+		CreatedBy: sr.CreatedBy,
+		CreatedAt: sr.CreatedAt,
+	}
+}
+
+func (s *Service) GetQuoteRevisions(ctx context.Context, req *GetQuoteRevisionsRequest) (*GetQuoteRevisionsResponse, error) {
+	if req.ID == 0 {
+		return nil, newValidationError("id", "id parameter is required")
+	}
+
+	revisions, err := s.DB.GetQuoteRevisions(ctx, req.ID)
+	if err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to get quote revisions", "id", req.ID, "error", err)
+		}
+		return nil, storageError("failed to get quote revisions", err)
+	}
+
+	owners := seesOwners(ctx)
+	result := make([]QuoteRevision, 0, len(revisions))
+	for _, r := range revisions {
+		revision := toAppQuoteRevision(r)
+		if !owners {
+			revision.CreatedBy = ""
+		}
+		result = append(result, revision)
+	}
+
+	return &GetQuoteRevisionsResponse{Revisions: result}, nil
+}
+
+// RestoreQuoteRevision rolls a quote back to an earlier revision. Like UpdateQuote
+// it is open to editors and admins, and fails with ErrConflict when another
+// quote has the restored text by now.
+func (s *Service) RestoreQuoteRevision(ctx context.Context, req *RestoreQuoteRevisionRequest) (*RestoreQuoteRevisionResponse, error) {
+	principal, err := requireRole(ctx, RoleEditor, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.ID == 0 {
+		return nil, newValidationError("id", "id parameter is required")
+	}
+	if req.Revision <= 0 {
+		return nil, newValidationError("revision", "revision must be positive")
+	}
+
+	reverted, err := s.DB.RevertQuote(withActor(ctx, principal.Subject), req.ID, req.Revision, req.IfVersions)
+	if err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to restore quote revision", "id", req.ID, "revision", req.Revision, "error", err)
+		}
+		return nil, storageError("failed to restore quote revision", err)
+	}
+
+	return &RestoreQuoteRevisionResponse{Quote: toAppQuote(reverted)}, nil
+}
+
+// DiffQuoteRevisions compares two revisions of a live quote field by field.
+func (s *Service) DiffQuoteRevisions(ctx context.Context, req *DiffQuoteRevisionsRequest) (*DiffQuoteRevisionsResponse, error) {
+	if req.ID == 0 {
+		return nil, newValidationError("id", "id parameter is required")
+	}
+	if req.To <= 0 {
+		return nil, newValidationError("revision", "revision must be positive")
+	}
+	from := req.From
+	if from == 0 {
+		if req.To == 1 {
+			return nil, newValidationError("against", "revision 1 has no previous revision")
+		}
+		from = req.To - 1
+	}
+	if from < 0 {
+		return nil, newValidationError("against", "revision must be positive")
+	}
+
+	revisions, err := s.DB.GetQuoteRevisions(ctx, req.ID)
+	if err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to get quote revisions", "id", req.ID, "error", err)
+		}
+		return nil, storageError("failed to diff quote revisions", err)
+	}
+	var a, b *storage.QuoteRevision
+	for _, r := range revisions {
+		if r.Revision == from {
+			a = r
+		}
+		if r.Revision == req.To {
+			b = r
+		}
+	}
+	if a == nil || b == nil {
+		missing := req.To
+		if a == nil {
+			missing = from
+		}
+		return nil, fmt.Errorf("failed to diff quote revisions: revision %d: %w", missing, ErrNotFound)
+	}
+
+	diff := RevisionDiff{From: from, To: req.To, Changes: []FieldChange{}}
+	if a.Author != b.Author {
+		diff.Changes = append(diff.Changes, FieldChange{Field: "author", From: a.Author, To: b.Author})
+	}
+	if a.Quote != b.Quote {
+		diff.Changes = append(diff.Changes, FieldChange{
+			Field: "quote", From: a.Quote, To: b.Quote, Edits: diffWords(a.Quote, b.Quote),
+		})
+	}
+	if a.Lang != b.Lang {
+		diff.Changes = append(diff.Changes, FieldChange{Field: "lang", From: a.Lang, To: b.Lang})
+	}
+	return &DiffQuoteRevisionsResponse{Diff: diff}, nil
+}
+
+// diffWords compares two texts as sequences of words and whitespace runs using
+// their longest common subsequence, merging adjacent edits of the same kind.
+func diffWords(from, to string) []TextEdit {
+	a, b := splitWords(from), splitWords(to)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	head := TextEdit{Op: EditEqual, Text: strings.Join(a[:prefix], "")}
+	tail := TextEdit{Op: EditEqual, Text: strings.Join(a[len(a)-suffix:], "")}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(a)*len(b) > maxDiffCells {
+		return mergeEdits([]TextEdit{
+			head,
+			{Op: EditDelete, Text: strings.Join(a, "")},
+			{Op: EditInsert, Text: strings.Join(b, "")},
+			tail,
+		})
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := []TextEdit{head}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, TextEdit{Op: EditEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, TextEdit{Op: EditDelete, Text: a[i]})
+			i++
+		default:
+			edits = append(edits, TextEdit{Op: EditInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, TextEdit{Op: EditDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, TextEdit{Op: EditInsert, Text: b[j]})
+	}
+	return mergeEdits(append(edits, tail))
+}
+
+// splitWords splits text into alternating runs of whitespace and other characters.
+func splitWords(text string) []string {
+	var words []string
+	start, space := 0, false
+	for i, r := range text {
+		if isSpace := unicode.IsSpace(r); i == 0 || isSpace != space {
+			if i > start {
+				words = append(words, text[start:i])
+			}
+			start, space = i, isSpace
+		}
+	}
+	if start < len(text) {
+		words = append(words, text[start:])
+	}
+	return words
+}
+
+func mergeEdits(edits []TextEdit) []TextEdit {
+	merged := make([]TextEdit, 0, len(edits))
+	for _, e := range edits {
+		if e.Text == "" {
+			continue
+		}
+		if n := len(merged); n > 0 && merged[n-1].Op == e.Op {
+			merged[n-1].Text += e.Text
+			continue
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"errors"
+)
+
+// Role decides which QuoteService operations a principal may call:
+// readers may only read, editors may also add and edit quotes and delete the
+// quotes they added, admins may do everything. Reading needs no token, so
+// RoleReader grants nothing beyond anonymous access; it names a valid token
+// that is not allowed to write.
+type Role string
+
+const (
+	RoleReader Role = "reader"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// ErrForbidden is returned when the principal is known but its role does not allow the operation.
+var ErrForbidden = errors.New("forbidden")
+
+func (r Role) valid() bool {
+	switch r {
+	case RoleReader, RoleEditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// requireRole returns the principal of ctx when it has one of roles. A request
+// without a principal is ErrUnauthorized, one with another role ErrForbidden.
+func requireRole(ctx context.Context, roles ...Role) (*Principal, error) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	for _, role := range roles {
+		if p.Role == role {
+			return p, nil
+		}
+	}
+	return nil, ErrForbidden
+}
+
+// seesOwners reports whether the caller of ctx may see who added or changed a
+// quote. CreatedBy is the token subject of an editor, so reads by readers and
+// anonymous callers leave it out.
+func seesOwners(ctx context.Context) bool {
+	p, ok := PrincipalFromContext(ctx)
+	return ok && (p.Role == RoleEditor || p.Role == RoleAdmin)
+}
+
+// ownerOf returns the subject whose quotes p may delete and restore: editors
+// only their own, admins any quote, which storage takes as an empty owner.
+// Authenticate never yields an empty subject, so an editor is always limited.
+func ownerOf(p *Principal) string {
+	if p.Role == RoleAdmin {
+		return ""
+	}
+	return p.Subject
+}
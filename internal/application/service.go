@@ -2,8 +2,12 @@ package application
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/azaliaz/quote-service/internal/storage"
+	"io"
 	"log/slog"
+	"sync"
 	"time"
 )
 
@@ -13,51 +17,364 @@ type QuoteService interface {
 	AddQuote(ctx context.Context, req *AddQuoteRequest) (*AddQuoteResponse, error)
 	GetQuotes(ctx context.Context, req *GetQuotesRequest) (*GetQuotesResponse, error)
 	GetRandomQuote(ctx context.Context, req *GetRandomQuoteRequest) (*GetRandomQuoteResponse, error)
+	GetQuote(ctx context.Context, req *GetQuoteRequest) (*GetQuoteResponse, error)
 	GetQuotesByAuthor(ctx context.Context, req *GetQuotesByAuthorRequest) (*GetQuotesByAuthorResponse, error)
+	UpdateQuote(ctx context.Context, req *UpdateQuoteRequest) (*UpdateQuoteResponse, error)
 	DeleteQuote(ctx context.Context, req *DeleteQuoteRequest) (*DeleteQuoteResponse, error)
+	SearchQuotes(ctx context.Context, req *SearchQuotesRequest) (*SearchQuotesResponse, error)
+	GetTags(ctx context.Context, req *GetTagsRequest) (*GetTagsResponse, error)
+	GetAuthors(ctx context.Context, req *GetAuthorsRequest) (*GetAuthorsResponse, error)
+	GetAuthor(ctx context.Context, req *GetAuthorRequest) (*GetAuthorResponse, error)
+	GetAuthorQuotes(ctx context.Context, req *GetAuthorQuotesRequest) (*GetAuthorQuotesResponse, error)
+	GetDailyQuote(ctx context.Context, req *GetDailyQuoteRequest) (*GetDailyQuoteResponse, error)
+	Authenticate(ctx context.Context, req *AuthenticateRequest) (*AuthenticateResponse, error)
+	GetTrash(ctx context.Context, req *GetTrashRequest) (*GetTrashResponse, error)
+	RestoreQuote(ctx context.Context, req *RestoreQuoteRequest) (*RestoreQuoteResponse, error)
+	GetAuditEvents(ctx context.Context, req *GetAuditEventsRequest) (*GetAuditEventsResponse, error)
+	GetQuoteRevisions(ctx context.Context, req *GetQuoteRevisionsRequest) (*GetQuoteRevisionsResponse, error)
+	RestoreQuoteRevision(ctx context.Context, req *RestoreQuoteRevisionRequest) (*RestoreQuoteRevisionResponse, error)
+	DiffQuoteRevisions(ctx context.Context, req *DiffQuoteRevisionsRequest) (*DiffQuoteRevisionsResponse, error)
+	ImportQuotes(ctx context.Context, req *ImportQuotesRequest) (*ImportQuotesResponse, error)
+	ExportQuotes(ctx context.Context, req *ExportQuotesRequest) error
 }
 type Quote struct {
 	ID        int64
+	AuthorID  int64
 	Author    string
 	Quote     string
+	Lang      string
+	CreatedBy string
+	Version   int
 	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time `json:",omitempty"`
+	Tags      []string
 }
+
+// AddQuoteRequest adds a quote. A quote whose text matches a stored one up to
+// case, whitespace and punctuation is a duplicate: it fails with
+// DuplicateQuoteError, or with Upsert set reports the stored quote instead.
 type AddQuoteRequest struct {
-	Author string `json:"author"`
-	Quote  string `json:"quote"`
+	Author string   `json:"author"`
+	Quote  string   `json:"quote"`
+	Tags   []string `json:"tags,omitempty"`
+	Lang   string   `json:"lang,omitempty"`
+	Upsert bool     `json:"-"`
 }
+
+// AddQuoteResponse holds the id and version of the added quote. Created is
+// false when an upsert found a duplicate; ID and Version are then those of
+// the stored quote.
 type AddQuoteResponse struct {
-	ID int64
+	ID      int64
+	Created bool
+	Version int
 }
 
-type GetQuotesRequest struct{}
+// GetQuotesRequest lists quotes, optionally filtered by author and tags.
+// Tags match quotes having any of them, or all of them with MatchAllTags.
+type GetQuotesRequest struct {
+	Author       string
+	Tags         []string
+	MatchAllTags bool
+	Limit        int
+	Cursor       string
+}
 type GetQuotesResponse struct {
-	Quotes []Quote
+	Quotes     []Quote
+	NextCursor string
 }
 
-type GetRandomQuoteRequest struct{}
+// GetRandomQuoteRequest picks a random quote among those matching the optional
+// filters, which combine like in GetQuotesRequest.
+type GetRandomQuoteRequest struct {
+	Author       string
+	Tags         []string
+	MatchAllTags bool
+	Lang         string
+}
 type GetRandomQuoteResponse struct {
 	Quote Quote
 }
 
+type GetQuoteRequest struct {
+	ID int64
+}
+type GetQuoteResponse struct {
+	Quote Quote
+}
+
 type GetQuotesByAuthorRequest struct {
 	Author string
+	Limit  int
+	Cursor string
 }
 type GetQuotesByAuthorResponse struct {
-	Quotes []Quote
+	Quotes     []Quote
+	NextCursor string
 }
 
+// UpdateQuoteRequest changes the author and/or text of a quote.
+// With Replace set both fields are required (PUT semantics), otherwise
+// only the non-nil fields are changed (PATCH semantics). A non-empty IfVersions
+// makes the update fail with ErrPreconditionFailed unless the quote is at one of those versions.
+type UpdateQuoteRequest struct {
+	ID         int64   `json:"-"`
+	Author     *string `json:"author"`
+	Quote      *string `json:"quote"`
+	Replace    bool    `json:"-"`
+	IfVersions []int   `json:"-"`
+}
+type UpdateQuoteResponse struct {
+	Quote Quote
+}
+
+// DeleteQuoteRequest moves a quote to the trash. IfVersions works like in UpdateQuoteRequest.
 type DeleteQuoteRequest struct {
-	ID int64
+	ID         int64
+	IfVersions []int
 }
 type DeleteQuoteResponse struct {
 	Success bool
 }
 
+type GetQuoteRevisionsRequest struct {
+	ID int64
+}
+type GetQuoteRevisionsResponse struct {
+	Revisions []QuoteRevision
+}
+
+// RestoreQuoteRevisionRequest rolls a quote back to the content of Revision.
+// The rollback itself becomes the newest revision. IfVersions works like in UpdateQuoteRequest.
+type RestoreQuoteRevisionRequest struct {
+	ID         int64
+	Revision   int
+	IfVersions []int
+}
+type RestoreQuoteRevisionResponse struct {
+	Quote Quote
+}
+
+// DiffQuoteRevisionsRequest compares revision To of a quote with revision From,
+// or with the revision before To when From is zero.
+type DiffQuoteRevisionsRequest struct {
+	ID   int64
+	From int
+	To   int
+}
+type DiffQuoteRevisionsResponse struct {
+	Diff RevisionDiff
+}
+
+// RevisionDiff lists the fields that differ between two revisions of a quote.
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is one changed field. For the quote text, Edits also spells out
+// the change word by word; joining the texts of the equal and delete edits
+// gives From, of the equal and insert edits gives To.
+type FieldChange struct {
+	Field string     `json:"field"`
+	From  string     `json:"from"`
+	To    string     `json:"to"`
+	Edits []TextEdit `json:"edits,omitempty"`
+}
+
+// Operations of a TextEdit.
+const (
+	EditEqual  = "equal"
+	EditDelete = "delete"
+	EditInsert = "insert"
+)
+
+// TextEdit is a run of text kept, removed or added between two revisions.
+type TextEdit struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// QuoteRevision is one past or current version of a quote. Revision numbers
+// start at 1 and grow with every change; CreatedBy made the change.
+type QuoteRevision struct {
+	Revision  int       `json:"revision"`
+	AuthorID  int64     `json:"author_id"`
+	Author    string    `json:"author"`
+	Quote     string    `json:"quote"`
+	Lang      string    `json:"lang,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ImportQuotesRequest adds every record of Body, decoded according to Format.
+// With DryRun set nothing is stored, but the report tells what would happen.
+type ImportQuotesRequest struct {
+	Format ImportFormat
+	Body   io.Reader
+	DryRun bool
+}
+
+// ImportQuotesResponse reports the outcome of every record in input order.
+type ImportQuotesResponse struct {
+	Inserted   int         `json:"inserted"`
+	Duplicates int         `json:"duplicates"`
+	Invalid    int         `json:"invalid"`
+	DryRun     bool        `json:"dry_run"`
+	Rows       []ImportRow `json:"rows"`
+}
+
+// ImportRow is the outcome of the Row-th record (counting from 1). ID is the
+// added quote, or the stored one for a duplicate; Error explains an invalid record.
+type ImportRow struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	ID     int64  `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ExportQuotesRequest writes the quotes matching the filters of GetQuotesRequest
+// to Out, encoded according to Format.
+type ExportQuotesRequest struct {
+	Format       ExportFormat
+	Author       string
+	Tags         []string
+	MatchAllTags bool
+	Out          io.Writer
+}
+
+// GetTrashRequest lists deleted quotes that have not been purged yet,
+// most recently deleted first.
+type GetTrashRequest struct {
+	Limit  int
+	Offset int
+}
+type GetTrashResponse struct {
+	Quotes []Quote
+}
+
+// RestoreQuoteRequest takes a quote out of the trash. IfVersions works like in UpdateQuoteRequest.
+type RestoreQuoteRequest struct {
+	ID         int64
+	IfVersions []int
+}
+type RestoreQuoteResponse struct {
+	Quote Quote
+}
+
+type SearchQuotesRequest struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+type SearchQuotesResponse struct {
+	Results []SearchResult
+}
+type SearchResult struct {
+	Quote    Quote
+	Rank     float32
+	Headline string
+}
+
+type GetTagsRequest struct{}
+type GetTagsResponse struct {
+	Tags []Tag
+}
+type Tag struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// GetDailyQuoteRequest asks for the quote of the day. Date (YYYY-MM-DD) looks
+// up a past day; empty means today in the configured timezone.
+type GetDailyQuoteRequest struct {
+	Date string
+}
+type GetDailyQuoteResponse struct {
+	Date  string
+	Quote Quote
+}
+
+// AuthenticateRequest carries a bearer token (an HS256 JWT) to verify.
+type AuthenticateRequest struct {
+	Token string
+}
+type AuthenticateResponse struct {
+	Principal Principal
+}
+
+// GetAuditEventsRequest lists audit events, optionally only those of one quote,
+// made by one actor, or recorded at or after Since (RFC 3339 or YYYY-MM-DD).
+type GetAuditEventsRequest struct {
+	QuoteID int64
+	Actor   string
+	Since   string
+	Limit   int
+	Offset  int
+}
+type GetAuditEventsResponse struct {
+	Events []AuditEvent
+}
+
+// AuditEvent is a recorded quote mutation. Before and After are snapshots of
+// the quote; Before is absent for creations and After for purges.
+type AuditEvent struct {
+	ID        int64           `json:"id"`
+	QuoteID   int64           `json:"quote_id"`
+	Actor     string          `json:"actor,omitempty"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type GetAuthorsRequest struct {
+	Limit  int
+	Offset int
+}
+type GetAuthorsResponse struct {
+	Authors []Author
+}
+
+type GetAuthorRequest struct {
+	ID int64
+}
+type GetAuthorResponse struct {
+	Author Author
+}
+
+type GetAuthorQuotesRequest struct {
+	ID     int64
+	Limit  int
+	Cursor string
+}
+type GetAuthorQuotesResponse struct {
+	Quotes     []Quote
+	NextCursor string
+}
+
+// Author is a canonical author. Quotes added under any of Aliases
+// (compared case-insensitively, ignoring surrounding spaces) belong to it.
+type Author struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases"`
+	Bio       string   `json:"bio,omitempty"`
+	BirthYear *int     `json:"birth_year,omitempty"`
+	DeathYear *int     `json:"death_year,omitempty"`
+}
+
 type Service struct {
 	Log    *slog.Logger
 	Config *Config
 	DB     storage.QuoteStorage
+
+	location *time.Location
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
 func NewService(
@@ -73,13 +390,49 @@ func NewService(
 }
 
 func (s *Service) Init() error {
+	s.done = make(chan struct{})
+	if s.Config != nil && s.Config.DailyTimezone != "" {
+		loc, err := time.LoadLocation(s.Config.DailyTimezone)
+		if err != nil {
+			return fmt.Errorf("invalid daily timezone %q: %w", s.Config.DailyTimezone, err)
+		}
+		s.location = loc
+	}
+	if s.Config != nil && s.Config.PurgeInterval > 0 && s.Config.TrashRetention <= 0 {
+		// The purge job would remove deleted quotes right away, leaving nothing to restore.
+		return fmt.Errorf("trash retention must be positive when the purge job is enabled, got %s", s.Config.TrashRetention)
+	}
+	if s.Config == nil || s.Config.Secret == "" {
+		s.Log.Warn("APP_SECRET is not set, write requests will be rejected")
+	}
 	return nil
 }
 
+// Run purges the trash every Config.PurgeInterval until Stop is called or ctx is done.
 func (s *Service) Run(ctx context.Context) {
+	if s.Config == nil || s.Config.PurgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.Config.PurgeInterval)
+	defer ticker.Stop()
 
+	for {
+		s.PurgeTrash(ctx)
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 func (s *Service) Stop() {
-
+	s.stopOnce.Do(func() {
+		if s.done != nil {
+			close(s.done)
+		}
+	})
 }
@@ -0,0 +1,34 @@
+package application
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const maxTagLength = 64
+
+// normalizeTags lower-cases and trims tag names and drops duplicates,
+// so "Humor" and " humor" end up as the same tag.
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name := strings.ToLower(strings.TrimSpace(tag))
+		if name == "" {
+			return nil, newValidationError("tags", "tag cannot be empty")
+		}
+		if utf8.RuneCountInString(name) > maxTagLength {
+			return nil, newValidationError("tags", "tag is too long")
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		result = append(result, name)
+	}
+	return result, nil
+}
@@ -2,7 +2,13 @@ package tests
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,6 +27,10 @@ func newTestLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, nil))
 }
 
+func contextAs(subject string, role application.Role) context.Context {
+	return application.WithPrincipal(context.Background(), &application.Principal{Subject: subject, Role: role})
+}
+
 func TestAddQuote(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -40,7 +50,7 @@ func TestAddQuote(t *testing.T) {
 					AddQuote(gomock.Any(), gomock.Any()).
 					Return(int64(1), nil)
 			},
-			want: &application.AddQuoteResponse{ID: 1},
+			want: &application.AddQuoteResponse{ID: 1, Created: true, Version: 1},
 			err:  "",
 		},
 		{
@@ -50,6 +60,53 @@ func TestAddQuote(t *testing.T) {
 			want: nil,
 			err:  "author and quote cannot be empty",
 		},
+		{
+			name: "only punctuation",
+			req:  &application.AddQuoteRequest{Author: "Author", Quote: " «…» — ?! "},
+			mock: nil,
+			want: nil,
+			err:  "quote must contain more than punctuation",
+		},
+		{
+			name: "with tags",
+			req:  &application.AddQuoteRequest{Author: "Author", Quote: "Quote", Tags: []string{"Humor", "humor ", "wit"}},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					AddQuote(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, q *storage.Quote) (int64, error) {
+						assert.Equal(t, []string{"humor", "wit"}, q.Tags)
+						assert.Equal(t, "editor", q.CreatedBy)
+						return 2, nil
+					})
+			},
+			want: &application.AddQuoteResponse{ID: 2, Created: true, Version: 1},
+			err:  "",
+		},
+		{
+			name: "duplicate",
+			req:  &application.AddQuoteRequest{Author: "Author", Quote: "Quote"},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					AddQuote(gomock.Any(), gomock.Any()).
+					Return(int64(0), &storage.DuplicateError{ID: 5})
+			},
+			want: nil,
+			err:  "quote already exists with id 5",
+		},
+		{
+			name: "upsert duplicate",
+			req:  &application.AddQuoteRequest{Author: "Author", Quote: "quote", Upsert: true},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					AddQuote(gomock.Any(), gomock.Any()).
+					Return(int64(0), &storage.DuplicateError{ID: 5})
+				m.EXPECT().
+					GetQuoteByID(gomock.Any(), int64(5)).
+					Return(&storage.Quote{ID: 5, Author: "Author", Quote: "Quote.", Version: 3}, nil)
+			},
+			want: &application.AddQuoteResponse{ID: 5, Version: 3},
+			err:  "",
+		},
 		{
 			name: "storage error",
 			req:  &application.AddQuoteRequest{Author: "Author", Quote: "Quote"},
@@ -75,7 +132,7 @@ func TestAddQuote(t *testing.T) {
 				Log: newTestLogger(),
 			}
 
-			resp, err := svc.AddQuote(context.Background(), tt.req)
+			resp, err := svc.AddQuote(contextAs("editor", application.RoleEditor), tt.req)
 			if tt.err != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.err)
@@ -94,11 +151,11 @@ func TestGetQuotes(t *testing.T) {
 
 	mockStorage := mocks.NewMockQuoteStorage(ctrl)
 	mockStorage.EXPECT().
-		GetAllQuotes(gomock.Any()).
+		GetAllQuotes(gomock.Any(), storage.QuoteFilter{}, storage.Page{Limit: 50}).
 		Return([]*storage.Quote{
 			{ID: 1, Author: "A", Quote: "Q1", CreatedAt: time.Now()},
 			{ID: 2, Author: "B", Quote: "Q2", CreatedAt: time.Now()},
-		}, nil)
+		}, nil, nil)
 
 	svc := &application.Service{
 		DB:  mockStorage,
@@ -110,6 +167,94 @@ func TestGetQuotes(t *testing.T) {
 	require.Len(t, resp.Quotes, 2)
 	assert.Equal(t, "A", resp.Quotes[0].Author)
 	assert.Equal(t, "Q1", resp.Quotes[0].Quote)
+	assert.Empty(t, resp.NextCursor)
+}
+
+func TestGetQuotes_Pagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createdAt := time.Date(2025, 6, 3, 13, 49, 49, 549181000, time.UTC)
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	first := mockStorage.EXPECT().
+		GetAllQuotes(gomock.Any(), storage.QuoteFilter{}, storage.Page{Limit: 1}).
+		Return([]*storage.Quote{{ID: 2, Author: "B", Quote: "Q2", CreatedAt: createdAt}},
+			&storage.Cursor{CreatedAt: createdAt, ID: 2}, nil)
+	mockStorage.EXPECT().
+		GetAllQuotes(gomock.Any(), storage.QuoteFilter{}, storage.Page{Limit: 1, After: &storage.Cursor{CreatedAt: createdAt, ID: 2}}).
+		Return([]*storage.Quote{{ID: 1, Author: "A", Quote: "Q1", CreatedAt: createdAt}}, nil, nil).
+		After(first)
+
+	svc := &application.Service{
+		DB:  mockStorage,
+		Log: newTestLogger(),
+	}
+
+	resp, err := svc.GetQuotes(context.Background(), &application.GetQuotesRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Quotes, 1)
+	require.NotEmpty(t, resp.NextCursor)
+
+	resp, err = svc.GetQuotes(context.Background(), &application.GetQuotesRequest{Limit: 1, Cursor: resp.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, resp.Quotes, 1)
+	assert.Equal(t, int64(1), resp.Quotes[0].ID)
+	assert.Empty(t, resp.NextCursor)
+
+	_, err = svc.GetQuotes(context.Background(), &application.GetQuotesRequest{Cursor: "not a cursor"})
+	assert.ErrorIs(t, err, application.ErrInvalidCursor)
+}
+
+func TestGetQuotes_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	mockStorage.EXPECT().
+		GetAllQuotes(gomock.Any(), storage.QuoteFilter{
+			Author:       "Seneca",
+			Tags:         []string{"stoicism", "time"},
+			MatchAllTags: true,
+		}, storage.Page{Limit: 50}).
+		Return([]*storage.Quote{
+			{ID: 1, Author: "Seneca", Quote: "Q1", Tags: []string{"stoicism", "time"}},
+		}, nil, nil)
+
+	svc := &application.Service{
+		DB:  mockStorage,
+		Log: newTestLogger(),
+	}
+
+	resp, err := svc.GetQuotes(context.Background(), &application.GetQuotesRequest{
+		Author:       "Seneca",
+		Tags:         []string{" Stoicism", "time", "TIME"},
+		MatchAllTags: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Quotes, 1)
+	assert.Equal(t, []string{"stoicism", "time"}, resp.Quotes[0].Tags)
+
+	_, err = svc.GetQuotes(context.Background(), &application.GetQuotesRequest{Tags: []string{" "}})
+	assert.ErrorIs(t, err, application.ErrValidation)
+}
+
+func TestGetTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	mockStorage.EXPECT().
+		GetTags(gomock.Any()).
+		Return([]*storage.Tag{{Name: "humor", Count: 3}, {Name: "leadership", Count: 1}}, nil)
+
+	svc := &application.Service{
+		DB:  mockStorage,
+		Log: newTestLogger(),
+	}
+
+	resp, err := svc.GetTags(context.Background(), &application.GetTagsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []application.Tag{{Name: "humor", Count: 3}, {Name: "leadership", Count: 1}}, resp.Tags)
 }
 
 func TestGetRandomQuote(t *testing.T) {
@@ -118,7 +263,7 @@ func TestGetRandomQuote(t *testing.T) {
 
 	mockStorage := mocks.NewMockQuoteStorage(ctrl)
 	mockStorage.EXPECT().
-		GetRandomQuote(gomock.Any()).
+		GetRandomQuote(gomock.Any(), storage.QuoteFilter{}).
 		Return(&storage.Quote{
 			ID:        1,
 			Author:    "Author",
@@ -137,6 +282,128 @@ func TestGetRandomQuote(t *testing.T) {
 	assert.Equal(t, "Random quote", resp.Quote.Quote)
 }
 
+func TestGetRandomQuote_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	ctx := context.Background()
+
+	t.Run("filters are normalized", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetRandomQuote(gomock.Any(), storage.QuoteFilter{
+				Author:       "Seneca",
+				Tags:         []string{"motivation"},
+				MatchAllTags: true,
+				Lang:         "en",
+			}).
+			Return(&storage.Quote{ID: 4, Author: "Seneca", Lang: "en"}, nil)
+
+		resp, err := svc.GetRandomQuote(ctx, &application.GetRandomQuoteRequest{
+			Author:       " Seneca ",
+			Tags:         []string{"Motivation"},
+			MatchAllTags: true,
+			Lang:         "EN",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "en", resp.Quote.Lang)
+	})
+
+	t.Run("nothing matches", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetRandomQuote(gomock.Any(), storage.QuoteFilter{Author: "Nobody"}).
+			Return(nil, storage.ErrNotFound)
+
+		_, err := svc.GetRandomQuote(ctx, &application.GetRandomQuoteRequest{Author: "Nobody"})
+		assert.ErrorIs(t, err, application.ErrNotFound)
+	})
+
+	t.Run("invalid lang", func(t *testing.T) {
+		_, err := svc.GetRandomQuote(ctx, &application.GetRandomQuoteRequest{Lang: "english"})
+		assert.ErrorIs(t, err, application.ErrValidation)
+	})
+}
+
+func TestGetQuote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name   string
+		req    *application.GetQuoteRequest
+		mock   func(m *mocks.MockQuoteStorage)
+		want   *application.GetQuoteResponse
+		errMsg string
+		errIs  error
+	}{
+		{
+			name: "success",
+			req:  &application.GetQuoteRequest{ID: 1},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					GetQuoteByID(gomock.Any(), int64(1)).
+					Return(&storage.Quote{ID: 1, Author: "Author", Quote: "Quote"}, nil)
+			},
+			want: &application.GetQuoteResponse{
+				Quote: application.Quote{ID: 1, Author: "Author", Quote: "Quote"},
+			},
+		},
+		{
+			name: "not found",
+			req:  &application.GetQuoteRequest{ID: 2},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					GetQuoteByID(gomock.Any(), int64(2)).
+					Return(nil, storage.ErrNotFound)
+			},
+			errIs: application.ErrNotFound,
+		},
+		{
+			name:   "empty id",
+			req:    &application.GetQuoteRequest{ID: 0},
+			errMsg: "id parameter is required",
+		},
+		{
+			name: "storage error",
+			req:  &application.GetQuoteRequest{ID: 1},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					GetQuoteByID(gomock.Any(), int64(1)).
+					Return(nil, errors.New("db error"))
+			},
+			errMsg: "failed to get quote: db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockQuoteStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(mockStorage)
+			}
+
+			svc := &application.Service{
+				DB:  mockStorage,
+				Log: newTestLogger(),
+			}
+
+			resp, err := svc.GetQuote(context.Background(), tt.req)
+			if tt.errIs != nil {
+				require.ErrorIs(t, err, tt.errIs)
+				assert.Nil(t, resp)
+			} else if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, resp)
+			}
+		})
+	}
+}
+
 func TestGetQuotesByAuthor(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -155,7 +422,7 @@ func TestGetQuotesByAuthor(t *testing.T) {
 			req:  &application.GetQuotesByAuthorRequest{Author: "Author"},
 			mock: func(m *mocks.MockQuoteStorage) {
 				m.EXPECT().
-					GetQuotesByAuthor(gomock.Any(), "Author").
+					GetQuotesByAuthor(gomock.Any(), "Author", gomock.Any()).
 					Return([]*storage.Quote{
 						{
 							ID:        1,
@@ -163,7 +430,7 @@ func TestGetQuotesByAuthor(t *testing.T) {
 							Quote:     "Quote 1",
 							CreatedAt: now,
 						},
-					}, nil)
+					}, nil, nil)
 			},
 			want: &application.GetQuotesByAuthorResponse{
 				Quotes: []application.Quote{
@@ -189,8 +456,8 @@ func TestGetQuotesByAuthor(t *testing.T) {
 			req:  &application.GetQuotesByAuthorRequest{Author: "Author"},
 			mock: func(m *mocks.MockQuoteStorage) {
 				m.EXPECT().
-					GetQuotesByAuthor(gomock.Any(), "Author").
-					Return(nil, errors.New("db error"))
+					GetQuotesByAuthor(gomock.Any(), "Author", gomock.Any()).
+					Return(nil, nil, errors.New("db error"))
 			},
 			want:   nil,
 			errMsg: "failed to get quotes by author: db error",
@@ -231,6 +498,117 @@ func TestGetQuotesByAuthor(t *testing.T) {
 	}
 }
 
+func TestUpdateQuote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	author := "New Author"
+	text := "New text"
+	empty := ""
+	dots := "..."
+
+	tests := []struct {
+		name   string
+		req    *application.UpdateQuoteRequest
+		mock   func(m *mocks.MockQuoteStorage)
+		want   *application.UpdateQuoteResponse
+		errMsg string
+		errIs  error
+	}{
+		{
+			name: "patch success",
+			req:  &application.UpdateQuoteRequest{ID: 1, Quote: &text},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					UpdateQuote(gomock.Any(), int64(1), &storage.QuoteUpdate{Quote: &text}).
+					Return(&storage.Quote{ID: 1, Author: "Author", Quote: text}, nil)
+			},
+			want: &application.UpdateQuoteResponse{
+				Quote: application.Quote{ID: 1, Author: "Author", Quote: text},
+			},
+		},
+		{
+			name: "put success",
+			req:  &application.UpdateQuoteRequest{ID: 1, Author: &author, Quote: &text, Replace: true},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					UpdateQuote(gomock.Any(), int64(1), &storage.QuoteUpdate{Author: &author, Quote: &text}).
+					Return(&storage.Quote{ID: 1, Author: author, Quote: text}, nil)
+			},
+			want: &application.UpdateQuoteResponse{
+				Quote: application.Quote{ID: 1, Author: author, Quote: text},
+			},
+		},
+		{
+			name:   "put without quote",
+			req:    &application.UpdateQuoteRequest{ID: 1, Author: &author, Replace: true},
+			errMsg: "author and quote are required",
+		},
+		{
+			name:   "nothing to update",
+			req:    &application.UpdateQuoteRequest{ID: 1},
+			errMsg: "nothing to update",
+		},
+		{
+			name:   "empty author",
+			req:    &application.UpdateQuoteRequest{ID: 1, Author: &empty},
+			errMsg: "author and quote cannot be empty",
+		},
+		{
+			name:   "only punctuation",
+			req:    &application.UpdateQuoteRequest{ID: 1, Quote: &dots},
+			errMsg: "quote must contain more than punctuation",
+		},
+		{
+			name: "not found",
+			req:  &application.UpdateQuoteRequest{ID: 2, Quote: &text},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					UpdateQuote(gomock.Any(), int64(2), gomock.Any()).
+					Return(nil, storage.ErrNotFound)
+			},
+			errIs: application.ErrNotFound,
+		},
+		{
+			name: "storage error",
+			req:  &application.UpdateQuoteRequest{ID: 1, Quote: &text},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					UpdateQuote(gomock.Any(), int64(1), gomock.Any()).
+					Return(nil, errors.New("db error"))
+			},
+			errMsg: "failed to update quote: db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockQuoteStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(mockStorage)
+			}
+
+			svc := &application.Service{
+				DB:  mockStorage,
+				Log: newTestLogger(),
+			}
+
+			resp, err := svc.UpdateQuote(contextAs("editor", application.RoleEditor), tt.req)
+			if tt.errIs != nil {
+				require.ErrorIs(t, err, tt.errIs)
+				assert.Nil(t, resp)
+			} else if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, resp)
+			}
+		})
+	}
+}
+
 func TestDeleteQuote(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -247,7 +625,7 @@ func TestDeleteQuote(t *testing.T) {
 			req:  &application.DeleteQuoteRequest{ID: 1},
 			mock: func(m *mocks.MockQuoteStorage) {
 				m.EXPECT().
-					DeleteQuote(gomock.Any(), int64(1)).
+					DeleteQuote(gomock.Any(), int64(1), "", nil).
 					Return(nil)
 			},
 			want:   &application.DeleteQuoteResponse{Success: true},
@@ -265,10 +643,10 @@ func TestDeleteQuote(t *testing.T) {
 			req:  &application.DeleteQuoteRequest{ID: 1},
 			mock: func(m *mocks.MockQuoteStorage) {
 				m.EXPECT().
-					DeleteQuote(gomock.Any(), int64(1)).
+					DeleteQuote(gomock.Any(), int64(1), "", nil).
 					Return(errors.New("db error"))
 			},
-			want:   &application.DeleteQuoteResponse{Success: false},
+			want:   nil,
 			errMsg: "failed to delete quote: db error",
 		},
 	}
@@ -285,16 +663,11 @@ func TestDeleteQuote(t *testing.T) {
 				Log: newTestLogger(),
 			}
 
-			resp, err := svc.DeleteQuote(context.Background(), tt.req)
+			resp, err := svc.DeleteQuote(contextAs("admin", application.RoleAdmin), tt.req)
 			if tt.errMsg != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errMsg)
-				if tt.want == nil {
-					assert.Nil(t, resp) // ожидаем nil, если want == nil
-				} else {
-					require.NotNil(t, resp)
-					assert.False(t, resp.Success)
-				}
+				assert.Nil(t, resp)
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tt.want, resp)
@@ -303,3 +676,1102 @@ func TestDeleteQuote(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchQuotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name   string
+		req    *application.SearchQuotesRequest
+		mock   func(m *mocks.MockQuoteStorage)
+		want   int
+		errMsg string
+	}{
+		{
+			name: "success",
+			req:  &application.SearchQuotesRequest{Query: " imagination "},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					SearchQuotes(gomock.Any(), "imagination", 50, 0).
+					Return([]*storage.SearchResult{
+						{
+							Quote:    storage.Quote{ID: 2, Author: "Albert Einstein", Quote: "Imagination is more important than knowledge."},
+							Rank:     0.6,
+							Headline: "<b>Imagination</b> is more important than knowledge.",
+						},
+					}, nil)
+			},
+			want: 1,
+		},
+		{
+			name:   "empty query",
+			req:    &application.SearchQuotesRequest{Query: "  "},
+			errMsg: "query parameter is required",
+		},
+		{
+			name: "storage error",
+			req:  &application.SearchQuotesRequest{Query: "knowledge", Limit: 1000},
+			mock: func(m *mocks.MockQuoteStorage) {
+				m.EXPECT().
+					SearchQuotes(gomock.Any(), "knowledge", 500, 0).
+					Return(nil, errors.New("db error"))
+			},
+			errMsg: "failed to search quotes: db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := mocks.NewMockQuoteStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(mockStorage)
+			}
+
+			svc := &application.Service{
+				DB:  mockStorage,
+				Log: newTestLogger(),
+			}
+
+			resp, err := svc.SearchQuotes(context.Background(), tt.req)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, resp.Results, tt.want)
+				assert.Equal(t, "Albert Einstein", resp.Results[0].Quote.Author)
+				assert.Contains(t, resp.Results[0].Headline, "<b>Imagination</b>")
+			}
+		})
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{
+		DB:  mockStorage,
+		Log: newTestLogger(),
+	}
+	ctx := contextAs("admin", application.RoleAdmin)
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := svc.AddQuote(ctx, &application.AddQuoteRequest{Author: "Author"})
+		require.ErrorIs(t, err, application.ErrValidation)
+
+		var vErr *application.ValidationError
+		require.ErrorAs(t, err, &vErr)
+		assert.Equal(t, "quote", vErr.Field)
+
+		_, err = svc.GetQuotes(ctx, &application.GetQuotesRequest{Cursor: "%%%"})
+		assert.ErrorIs(t, err, application.ErrValidation)
+	})
+
+	t.Run("duplicate quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			AddQuote(gomock.Any(), gomock.Any()).
+			Return(int64(0), &storage.DuplicateError{ID: 3})
+
+		_, err := svc.AddQuote(ctx, &application.AddQuoteRequest{Author: "Author", Quote: "Quote"})
+		require.ErrorIs(t, err, application.ErrConflict)
+
+		var dupErr *application.DuplicateQuoteError
+		require.ErrorAs(t, err, &dupErr)
+		assert.Equal(t, int64(3), dupErr.ExistingID)
+	})
+
+	t.Run("delete missing quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			DeleteQuote(gomock.Any(), int64(7), "", nil).
+			Return(fmt.Errorf("quote with id 7: %w", storage.ErrNotFound))
+
+		_, err := svc.DeleteQuote(ctx, &application.DeleteQuoteRequest{ID: 7})
+		assert.ErrorIs(t, err, application.ErrNotFound)
+	})
+
+	t.Run("random quote from empty table", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetRandomQuote(gomock.Any(), gomock.Any()).
+			Return(nil, storage.ErrNotFound)
+
+		_, err := svc.GetRandomQuote(ctx, &application.GetRandomQuoteRequest{})
+		assert.ErrorIs(t, err, application.ErrNotFound)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		mockStorage.EXPECT().
+			AddQuote(gomock.Any(), gomock.Any()).
+			Return(int64(0), storage.ErrConflict)
+
+		_, err := svc.AddQuote(ctx, &application.AddQuoteRequest{Author: "Author", Quote: "Quote"})
+		assert.ErrorIs(t, err, application.ErrConflict)
+	})
+
+	t.Run("stale version", func(t *testing.T) {
+		text := "Edited"
+		mockStorage.EXPECT().
+			UpdateQuote(gomock.Any(), int64(1), &storage.QuoteUpdate{Quote: &text, Versions: []int{2}}).
+			Return(nil, storage.ErrVersionMismatch)
+		mockStorage.EXPECT().
+			DeleteQuote(gomock.Any(), int64(1), "", []int{2}).
+			Return(storage.ErrVersionMismatch)
+
+		_, err := svc.UpdateQuote(ctx, &application.UpdateQuoteRequest{ID: 1, Quote: &text, IfVersions: []int{2}})
+		assert.ErrorIs(t, err, application.ErrPreconditionFailed)
+		_, err = svc.DeleteQuote(ctx, &application.DeleteQuoteRequest{ID: 1, IfVersions: []int{2}})
+		assert.ErrorIs(t, err, application.ErrPreconditionFailed)
+	})
+}
+
+func TestGetAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+	born := -551
+	mockStorage.EXPECT().
+		GetAuthors(gomock.Any(), 50, 10).
+		Return([]*storage.Author{
+			{ID: 1, Name: "Confucius", Aliases: []string{"confucius", "конфуций"}, BirthYear: &born},
+		}, nil)
+
+	resp, err := svc.GetAuthors(context.Background(), &application.GetAuthorsRequest{Offset: 10})
+	require.NoError(t, err)
+	require.Len(t, resp.Authors, 1)
+	assert.Equal(t, "Confucius", resp.Authors[0].Name)
+	assert.Equal(t, []string{"confucius", "конфуций"}, resp.Authors[0].Aliases)
+	assert.Equal(t, -551, *resp.Authors[0].BirthYear)
+
+	_, err = svc.GetAuthors(context.Background(), &application.GetAuthorsRequest{Offset: -1})
+	assert.ErrorIs(t, err, application.ErrValidation)
+}
+
+func TestGetAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	ctx := context.Background()
+
+	mockStorage.EXPECT().
+		GetAuthorByID(gomock.Any(), int64(1)).
+		Return(&storage.Author{ID: 1, Name: "Confucius"}, nil)
+	resp, err := svc.GetAuthor(ctx, &application.GetAuthorRequest{ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Confucius", resp.Author.Name)
+
+	mockStorage.EXPECT().
+		GetAuthorByID(gomock.Any(), int64(2)).
+		Return(nil, storage.ErrNotFound)
+	_, err = svc.GetAuthor(ctx, &application.GetAuthorRequest{ID: 2})
+	assert.ErrorIs(t, err, application.ErrNotFound)
+
+	_, err = svc.GetAuthor(ctx, &application.GetAuthorRequest{})
+	assert.ErrorIs(t, err, application.ErrValidation)
+}
+
+func TestGetAuthorQuotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetAuthorByID(gomock.Any(), int64(1)).
+			Return(&storage.Author{ID: 1, Name: "Confucius"}, nil)
+		mockStorage.EXPECT().
+			GetAllQuotes(gomock.Any(), storage.QuoteFilter{AuthorID: 1}, storage.Page{Limit: 2}).
+			Return([]*storage.Quote{
+				{ID: 3, AuthorID: 1, Author: "Confucius", Quote: "Quote 3"},
+				{ID: 1, AuthorID: 1, Author: "Confucius", Quote: "Quote 1"},
+			}, &storage.Cursor{CreatedAt: time.Unix(0, 1), ID: 1}, nil)
+
+		resp, err := svc.GetAuthorQuotes(ctx, &application.GetAuthorQuotesRequest{ID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, resp.Quotes, 2)
+		assert.Equal(t, int64(1), resp.Quotes[0].AuthorID)
+		assert.NotEmpty(t, resp.NextCursor)
+	})
+
+	t.Run("unknown author", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetAuthorByID(gomock.Any(), int64(9)).
+			Return(nil, storage.ErrNotFound)
+
+		_, err := svc.GetAuthorQuotes(ctx, &application.GetAuthorQuotesRequest{ID: 9})
+		assert.ErrorIs(t, err, application.ErrNotFound)
+	})
+}
+
+func TestGetDailyQuote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	ctx := context.Background()
+	today := time.Now().UTC().Format("2006-01-02")
+
+	t.Run("today picks a quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			PickDailyQuote(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, day time.Time) (*storage.Quote, error) {
+				assert.Equal(t, today, day.Format("2006-01-02"))
+				return &storage.Quote{ID: 3, Author: "Seneca"}, nil
+			})
+
+		resp, err := svc.GetDailyQuote(ctx, &application.GetDailyQuoteRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, today, resp.Date)
+		assert.Equal(t, int64(3), resp.Quote.ID)
+	})
+
+	t.Run("past date is looked up", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetDailyQuote(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, day time.Time) (*storage.Quote, error) {
+				assert.Equal(t, "2024-02-29", day.Format("2006-01-02"))
+				return &storage.Quote{ID: 1}, nil
+			})
+
+		resp, err := svc.GetDailyQuote(ctx, &application.GetDailyQuoteRequest{Date: "2024-02-29"})
+		require.NoError(t, err)
+		assert.Equal(t, "2024-02-29", resp.Date)
+	})
+
+	t.Run("past date without selection", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetDailyQuote(gomock.Any(), gomock.Any()).
+			Return(nil, storage.ErrNotFound)
+
+		_, err := svc.GetDailyQuote(ctx, &application.GetDailyQuoteRequest{Date: "2020-01-01"})
+		assert.ErrorIs(t, err, application.ErrNotFound)
+	})
+
+	t.Run("future date", func(t *testing.T) {
+		future := time.Now().UTC().AddDate(0, 0, 2).Format("2006-01-02")
+		_, err := svc.GetDailyQuote(ctx, &application.GetDailyQuoteRequest{Date: future})
+		assert.ErrorIs(t, err, application.ErrValidation)
+	})
+
+	t.Run("malformed date", func(t *testing.T) {
+		_, err := svc.GetDailyQuote(ctx, &application.GetDailyQuoteRequest{Date: "29.02.2024"})
+		assert.ErrorIs(t, err, application.ErrValidation)
+	})
+}
+
+func TestServiceInit_DailyTimezone(t *testing.T) {
+	svc := application.NewService(newTestLogger(), &application.Config{DailyTimezone: "Europe/Moscow"}, nil)
+	require.NoError(t, svc.Init())
+
+	svc = application.NewService(newTestLogger(), &application.Config{DailyTimezone: "Mars/Olympus"}, nil)
+	assert.Error(t, svc.Init())
+}
+
+func TestServiceInit_TrashRetention(t *testing.T) {
+	svc := application.NewService(newTestLogger(), &application.Config{PurgeInterval: time.Hour, TrashRetention: 0}, nil)
+	assert.Error(t, svc.Init())
+
+	svc = application.NewService(newTestLogger(), &application.Config{PurgeInterval: time.Hour, TrashRetention: -time.Hour}, nil)
+	assert.Error(t, svc.Init())
+
+	svc = application.NewService(newTestLogger(), &application.Config{TrashRetention: 0}, nil)
+	assert.NoError(t, svc.Init())
+}
+
+func signToken(t *testing.T, secret string, header, claims map[string]any) string {
+	t.Helper()
+	encode := func(v map[string]any) string {
+		raw, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	unsigned := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthenticate(t *testing.T) {
+	svc := &application.Service{
+		Config: &application.Config{Secret: "test-secret"},
+		Log:    newTestLogger(),
+	}
+	hs256 := map[string]any{"alg": "HS256", "typ": "JWT"}
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name    string
+		token   string
+		subject string
+	}{
+		{
+			name:    "valid",
+			token:   signToken(t, "test-secret", hs256, map[string]any{"sub": "importer", "exp": now + 60}),
+			subject: "importer",
+		},
+		{
+			name:  "wrong secret",
+			token: signToken(t, "other-secret", hs256, map[string]any{"sub": "importer"}),
+		},
+		{
+			name:  "expired",
+			token: signToken(t, "test-secret", hs256, map[string]any{"sub": "importer", "exp": now - 60}),
+		},
+		{
+			name:  "not yet valid",
+			token: signToken(t, "test-secret", hs256, map[string]any{"sub": "importer", "nbf": now + 60}),
+		},
+		{
+			name:  "alg none",
+			token: signToken(t, "test-secret", map[string]any{"alg": "none"}, map[string]any{"sub": "importer"}),
+		},
+		{
+			name:  "no subject",
+			token: signToken(t, "test-secret", hs256, map[string]any{"exp": now + 60}),
+		},
+		{
+			name:  "unknown role",
+			token: signToken(t, "test-secret", hs256, map[string]any{"sub": "importer", "role": "superuser"}),
+		},
+		{
+			name:  "garbage",
+			token: "not-a-jwt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.Authenticate(context.Background(), &application.AuthenticateRequest{Token: tt.token})
+			if tt.subject == "" {
+				assert.ErrorIs(t, err, application.ErrUnauthorized)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.subject, resp.Principal.Subject)
+			assert.Equal(t, application.RoleReader, resp.Principal.Role)
+		})
+	}
+
+	t.Run("role claim", func(t *testing.T) {
+		token := signToken(t, "test-secret", hs256, map[string]any{"sub": "importer", "role": "editor"})
+		resp, err := svc.Authenticate(context.Background(), &application.AuthenticateRequest{Token: token})
+		require.NoError(t, err)
+		assert.Equal(t, application.RoleEditor, resp.Principal.Role)
+	})
+
+	t.Run("no secret configured", func(t *testing.T) {
+		noSecret := &application.Service{Config: &application.Config{}, Log: newTestLogger()}
+		_, err := noSecret.Authenticate(context.Background(), &application.AuthenticateRequest{Token: tests[0].token})
+		assert.ErrorIs(t, err, application.ErrUnauthorized)
+	})
+}
+
+func TestRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	text := "Quote"
+
+	t.Run("anonymous cannot write", func(t *testing.T) {
+		_, err := svc.AddQuote(context.Background(), &application.AddQuoteRequest{Author: "Author", Quote: "Quote"})
+		assert.ErrorIs(t, err, application.ErrUnauthorized)
+	})
+
+	t.Run("reader cannot add, edit or delete", func(t *testing.T) {
+		ctx := contextAs("reader", application.RoleReader)
+
+		_, err := svc.AddQuote(ctx, &application.AddQuoteRequest{Author: "Author", Quote: "Quote"})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+		_, err = svc.UpdateQuote(ctx, &application.UpdateQuoteRequest{ID: 1, Quote: &text})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+		_, err = svc.DeleteQuote(ctx, &application.DeleteQuoteRequest{ID: 1})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+	})
+
+	t.Run("editor deletes own quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			DeleteQuote(gomock.Any(), int64(1), "alice", nil).
+			Return(nil)
+
+		resp, err := svc.DeleteQuote(contextAs("alice", application.RoleEditor), &application.DeleteQuoteRequest{ID: 1})
+		require.NoError(t, err)
+		assert.True(t, resp.Success)
+	})
+
+	t.Run("editor cannot delete others' quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			DeleteQuote(gomock.Any(), int64(2), "alice", nil).
+			Return(storage.ErrNotOwner)
+
+		_, err := svc.DeleteQuote(contextAs("alice", application.RoleEditor), &application.DeleteQuoteRequest{ID: 2})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+	})
+
+	t.Run("only editors and admins see who added a quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetQuoteByID(gomock.Any(), int64(3)).
+			Return(&storage.Quote{ID: 3, CreatedBy: "alice"}, nil).
+			Times(3)
+
+		resp, err := svc.GetQuote(context.Background(), &application.GetQuoteRequest{ID: 3})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Quote.CreatedBy)
+		resp, err = svc.GetQuote(contextAs("reader", application.RoleReader), &application.GetQuoteRequest{ID: 3})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Quote.CreatedBy)
+		resp, err = svc.GetQuote(contextAs("bob", application.RoleEditor), &application.GetQuoteRequest{ID: 3})
+		require.NoError(t, err)
+		assert.Equal(t, "alice", resp.Quote.CreatedBy)
+	})
+
+	t.Run("admin deletes any quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			DeleteQuote(gomock.Any(), int64(2), "", nil).
+			Return(nil)
+
+		_, err := svc.DeleteQuote(contextAs("root", application.RoleAdmin), &application.DeleteQuoteRequest{ID: 2})
+		require.NoError(t, err)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("reader cannot see or restore trash", func(t *testing.T) {
+		ctx := contextAs("reader", application.RoleReader)
+
+		_, err := svc.GetTrash(ctx, &application.GetTrashRequest{})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+		_, err = svc.RestoreQuote(ctx, &application.RestoreQuoteRequest{ID: 1})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetDeletedQuotes(gomock.Any(), 50, 0).
+			Return([]*storage.Quote{{ID: 1, Author: "Author", Quote: "Quote", DeletedAt: &deletedAt}}, nil)
+
+		resp, err := svc.GetTrash(contextAs("alice", application.RoleEditor), &application.GetTrashRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Quotes, 1)
+		assert.Equal(t, &deletedAt, resp.Quotes[0].DeletedAt)
+	})
+
+	t.Run("editor restores own quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			RestoreQuote(gomock.Any(), int64(1), "alice", nil).
+			Return(&storage.Quote{ID: 1, CreatedBy: "alice"}, nil)
+
+		resp, err := svc.RestoreQuote(contextAs("alice", application.RoleEditor), &application.RestoreQuoteRequest{ID: 1})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Quote.DeletedAt)
+	})
+
+	t.Run("editor cannot restore others' quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			RestoreQuote(gomock.Any(), int64(2), "alice", nil).
+			Return(nil, storage.ErrNotOwner)
+
+		_, err := svc.RestoreQuote(contextAs("alice", application.RoleEditor), &application.RestoreQuoteRequest{ID: 2})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+	})
+
+	t.Run("restore not in trash", func(t *testing.T) {
+		mockStorage.EXPECT().
+			RestoreQuote(gomock.Any(), int64(3), "", nil).
+			Return(nil, storage.ErrNotFound)
+
+		_, err := svc.RestoreQuote(contextAs("root", application.RoleAdmin), &application.RestoreQuoteRequest{ID: 3})
+		assert.ErrorIs(t, err, application.ErrNotFound)
+	})
+
+	t.Run("restore over a re-added duplicate", func(t *testing.T) {
+		mockStorage.EXPECT().
+			RestoreQuote(gomock.Any(), int64(4), "", nil).
+			Return(nil, storage.ErrConflict)
+
+		_, err := svc.RestoreQuote(contextAs("root", application.RoleAdmin), &application.RestoreQuoteRequest{ID: 4})
+		assert.ErrorIs(t, err, application.ErrConflict)
+	})
+}
+
+func TestPurgeTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{
+		DB:     mockStorage,
+		Log:    newTestLogger(),
+		Config: &application.Config{TrashRetention: 24 * time.Hour},
+	}
+
+	mockStorage.EXPECT().
+		PurgeDeletedQuotes(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now().Add(-24*time.Hour), before, time.Minute)
+			return 2, nil
+		})
+
+	svc.PurgeTrash(context.Background())
+
+	// Without a retention nothing is purged: the mock fails on an unexpected call.
+	svc.Config.TrashRetention = 0
+	svc.PurgeTrash(context.Background())
+}
+
+func TestAuditActor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	ctx := application.WithRequestID(contextAs("alice", application.RoleEditor), "req-1")
+
+	mockStorage.EXPECT().
+		AddQuote(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ *storage.Quote) (int64, error) {
+			assert.Equal(t, storage.Actor{Subject: "alice", RequestID: "req-1"}, storage.ActorFromContext(ctx))
+			return 1, nil
+		})
+
+	_, err := svc.AddQuote(ctx, &application.AddQuoteRequest{Author: "Author", Quote: "Quote"})
+	require.NoError(t, err)
+}
+
+func TestGetAuditEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	admin := contextAs("root", application.RoleAdmin)
+
+	t.Run("editor cannot read audit", func(t *testing.T) {
+		_, err := svc.GetAuditEvents(contextAs("alice", application.RoleEditor), &application.GetAuditEventsRequest{})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetAuditEvents(gomock.Any(), storage.AuditFilter{
+				QuoteID: 7,
+				Actor:   "alice",
+				Since:   time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			}, 50, 0).
+			Return([]*storage.AuditEvent{{
+				ID:      1,
+				QuoteID: 7,
+				Actor:   "alice",
+				Action:  storage.AuditCreate,
+				After:   json.RawMessage(`{"ID":7}`),
+			}}, nil)
+
+		resp, err := svc.GetAuditEvents(admin, &application.GetAuditEventsRequest{QuoteID: 7, Actor: "alice", Since: "2025-06-01"})
+		require.NoError(t, err)
+		require.Len(t, resp.Events, 1)
+		assert.Equal(t, "create", resp.Events[0].Action)
+		assert.JSONEq(t, `{"ID":7}`, string(resp.Events[0].After))
+	})
+
+	t.Run("since as timestamp", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetAuditEvents(gomock.Any(), storage.AuditFilter{Since: time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)}, 50, 0).
+			Return(nil, nil)
+
+		_, err := svc.GetAuditEvents(admin, &application.GetAuditEventsRequest{Since: "2025-06-01T12:00:00+03:00"})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		_, err := svc.GetAuditEvents(admin, &application.GetAuditEventsRequest{Since: "yesterday"})
+		assert.ErrorIs(t, err, application.ErrValidation)
+	})
+}
+
+func TestQuoteRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+	t.Run("list", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetQuoteRevisions(gomock.Any(), int64(1)).
+			Return([]*storage.QuoteRevision{
+				{QuoteID: 1, Revision: 2, Quote: "Edited", CreatedBy: "bob"},
+				{QuoteID: 1, Revision: 1, Quote: "Original", CreatedBy: "alice"},
+			}, nil)
+
+		resp, err := svc.GetQuoteRevisions(contextAs("carol", application.RoleEditor), &application.GetQuoteRevisionsRequest{ID: 1})
+		require.NoError(t, err)
+		require.Len(t, resp.Revisions, 2)
+		assert.Equal(t, 2, resp.Revisions[0].Revision)
+		assert.Equal(t, "alice", resp.Revisions[1].CreatedBy)
+	})
+
+	t.Run("list hides editors from anonymous callers", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetQuoteRevisions(gomock.Any(), int64(1)).
+			Return([]*storage.QuoteRevision{{QuoteID: 1, Revision: 1, Quote: "Original", CreatedBy: "alice"}}, nil)
+
+		resp, err := svc.GetQuoteRevisions(context.Background(), &application.GetQuoteRevisionsRequest{ID: 1})
+		require.NoError(t, err)
+		require.Len(t, resp.Revisions, 1)
+		assert.Empty(t, resp.Revisions[0].CreatedBy)
+	})
+
+	t.Run("list of missing quote", func(t *testing.T) {
+		mockStorage.EXPECT().
+			GetQuoteRevisions(gomock.Any(), int64(2)).
+			Return(nil, storage.ErrNotFound)
+
+		_, err := svc.GetQuoteRevisions(context.Background(), &application.GetQuoteRevisionsRequest{ID: 2})
+		assert.ErrorIs(t, err, application.ErrNotFound)
+	})
+
+	t.Run("reader cannot restore", func(t *testing.T) {
+		_, err := svc.RestoreQuoteRevision(contextAs("reader", application.RoleReader),
+			&application.RestoreQuoteRevisionRequest{ID: 1, Revision: 1})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+	})
+
+	t.Run("invalid revision", func(t *testing.T) {
+		_, err := svc.RestoreQuoteRevision(contextAs("alice", application.RoleEditor),
+			&application.RestoreQuoteRevisionRequest{ID: 1})
+		assert.ErrorIs(t, err, application.ErrValidation)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		mockStorage.EXPECT().
+			RevertQuote(gomock.Any(), int64(1), 1, []int{2}).
+			DoAndReturn(func(ctx context.Context, id int64, _ int, _ []int) (*storage.Quote, error) {
+				assert.Equal(t, "alice", storage.ActorFromContext(ctx).Subject)
+				return &storage.Quote{ID: id, Quote: "Original"}, nil
+			})
+
+		resp, err := svc.RestoreQuoteRevision(contextAs("alice", application.RoleEditor),
+			&application.RestoreQuoteRevisionRequest{ID: 1, Revision: 1, IfVersions: []int{2}})
+		require.NoError(t, err)
+		assert.Equal(t, "Original", resp.Quote.Quote)
+	})
+}
+
+func TestDiffQuoteRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockQuoteStorage(ctrl)
+	svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+	revisions := []*storage.QuoteRevision{
+		{QuoteID: 1, Revision: 3, Author: "Seneca", Quote: "Luck is what happens when  preparation meets opportunity.", Lang: "en"},
+		{QuoteID: 1, Revision: 2, Author: "Seneca", Quote: "Luck is what happens when preparation meets chance."},
+		{QuoteID: 1, Revision: 1, Author: "Unknown", Quote: "Luck is what happens when preparation meets chance."},
+	}
+
+	t.Run("against previous revision", func(t *testing.T) {
+		mockStorage.EXPECT().GetQuoteRevisions(gomock.Any(), int64(1)).Return(revisions, nil)
+
+		resp, err := svc.DiffQuoteRevisions(context.Background(), &application.DiffQuoteRevisionsRequest{ID: 1, To: 3})
+		require.NoError(t, err)
+		assert.Equal(t, application.RevisionDiff{
+			From: 2,
+			To:   3,
+			Changes: []application.FieldChange{
+				{
+					Field: "quote",
+					From:  revisions[1].Quote,
+					To:    revisions[0].Quote,
+					Edits: []application.TextEdit{
+						{Op: application.EditEqual, Text: "Luck is what happens when"},
+						{Op: application.EditDelete, Text: " "},
+						{Op: application.EditInsert, Text: "  "},
+						{Op: application.EditEqual, Text: "preparation meets "},
+						{Op: application.EditDelete, Text: "chance."},
+						{Op: application.EditInsert, Text: "opportunity."},
+					},
+				},
+				{Field: "lang", From: "", To: "en"},
+			},
+		}, resp.Diff)
+	})
+
+	t.Run("against an explicit revision", func(t *testing.T) {
+		mockStorage.EXPECT().GetQuoteRevisions(gomock.Any(), int64(1)).Return(revisions, nil)
+
+		resp, err := svc.DiffQuoteRevisions(context.Background(), &application.DiffQuoteRevisionsRequest{ID: 1, From: 1, To: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []application.FieldChange{{Field: "author", From: "Unknown", To: "Seneca"}}, resp.Diff.Changes)
+	})
+
+	t.Run("same revision", func(t *testing.T) {
+		mockStorage.EXPECT().GetQuoteRevisions(gomock.Any(), int64(1)).Return(revisions, nil)
+
+		resp, err := svc.DiffQuoteRevisions(context.Background(), &application.DiffQuoteRevisionsRequest{ID: 1, From: 2, To: 2})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Diff.Changes)
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		mockStorage.EXPECT().GetQuoteRevisions(gomock.Any(), int64(1)).Return(revisions, nil)
+
+		_, err := svc.DiffQuoteRevisions(context.Background(), &application.DiffQuoteRevisionsRequest{ID: 1, To: 9})
+		assert.ErrorIs(t, err, application.ErrNotFound)
+	})
+
+	t.Run("first revision has no previous one", func(t *testing.T) {
+		_, err := svc.DiffQuoteRevisions(context.Background(), &application.DiffQuoteRevisionsRequest{ID: 1, To: 1})
+		assert.ErrorIs(t, err, application.ErrValidation)
+	})
+
+	t.Run("changed part over the size limit", func(t *testing.T) {
+		from, to := make([]string, 150), make([]string, 150)
+		for i := range from {
+			from[i], to[i] = fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+		}
+		long := []*storage.QuoteRevision{
+			{QuoteID: 1, Revision: 2, Author: "Seneca", Quote: "Intro " + strings.Join(to, " ") + " outro"},
+			{QuoteID: 1, Revision: 1, Author: "Seneca", Quote: "Intro " + strings.Join(from, " ") + " outro"},
+		}
+		mockStorage.EXPECT().GetQuoteRevisions(gomock.Any(), int64(1)).Return(long, nil)
+
+		resp, err := svc.DiffQuoteRevisions(context.Background(), &application.DiffQuoteRevisionsRequest{ID: 1, To: 2})
+		require.NoError(t, err)
+		require.Len(t, resp.Diff.Changes, 1)
+		assert.Equal(t, []application.TextEdit{
+			{Op: application.EditEqual, Text: "Intro "},
+			{Op: application.EditDelete, Text: strings.Join(from, " ")},
+			{Op: application.EditInsert, Text: strings.Join(to, " ")},
+			{Op: application.EditEqual, Text: " outro"},
+		}, resp.Diff.Changes[0].Edits)
+	})
+}
+
+func TestImportQuotes(t *testing.T) {
+	ctx := contextAs("alice", application.RoleEditor)
+
+	// importAll answers every batch by marking quotes whose text is "dup" as duplicates of quote 1.
+	importAll := func(m *mocks.MockQuoteStorage, dryRun bool) {
+		next := int64(100)
+		m.EXPECT().
+			ImportQuotes(gomock.Any(), gomock.Any(), dryRun).
+			DoAndReturn(func(_ context.Context, quotes []*storage.Quote, _ bool) ([]storage.ImportOutcome, error) {
+				outcomes := make([]storage.ImportOutcome, len(quotes))
+				for i, q := range quotes {
+					assert.Equal(t, "alice", q.CreatedBy)
+					if q.Quote == "dup" {
+						outcomes[i] = storage.ImportOutcome{Duplicate: true, ID: 1}
+						continue
+					}
+					next++
+					outcomes[i] = storage.ImportOutcome{ID: next}
+				}
+				return outcomes, nil
+			}).
+			AnyTimes()
+	}
+
+	tests := []struct {
+		name   string
+		format application.ImportFormat
+		body   string
+		want   []application.ImportRow
+	}{
+		{
+			name:   "json array",
+			format: application.ImportJSON,
+			body:   `[{"author":"A","quote":"one","tags":["x"]},{"author":"A","quote":"dup"},{"author":"","quote":"no author"},42,{"author":"B","quote":"two","lang":"en"}]`,
+			want: []application.ImportRow{
+				{Row: 1, Status: application.ImportInserted, ID: 101},
+				{Row: 2, Status: application.ImportDuplicate, ID: 1},
+				{Row: 3, Status: application.ImportInvalid, Error: "author and quote cannot be empty"},
+				{Row: 4, Status: application.ImportInvalid},
+				{Row: 5, Status: application.ImportInserted, ID: 102},
+			},
+		},
+		{
+			name:   "truncated json",
+			format: application.ImportJSON,
+			body:   `[{"author":"A","quote":"one"},{"author":`,
+			want: []application.ImportRow{
+				{Row: 1, Status: application.ImportInserted, ID: 101},
+				{Row: 2, Status: application.ImportInvalid},
+			},
+		},
+		{
+			name:   "ndjson",
+			format: application.ImportNDJSON,
+			body:   "{\"author\":\"A\",\"quote\":\"one\"}\n\nnot json\n{\"author\":\"A\",\"quote\":\"two\",\"lang\":\"english\"}\n{\"author\":\"A\",\"quote\":\"dup\"}\n",
+			want: []application.ImportRow{
+				{Row: 1, Status: application.ImportInserted, ID: 101},
+				{Row: 2, Status: application.ImportInvalid},
+				{Row: 3, Status: application.ImportInvalid, Error: "lang must be a 2 or 3 letter language code"},
+				{Row: 4, Status: application.ImportDuplicate, ID: 1},
+			},
+		},
+		{
+			name:   "csv",
+			format: application.ImportCSV,
+			body:   "Quote,Author,Tags\n\"one, with comma\",A,\"x, y\"\ntoo,few\ndup,A,\n",
+			want: []application.ImportRow{
+				{Row: 1, Status: application.ImportInserted, ID: 101},
+				{Row: 2, Status: application.ImportInvalid},
+				{Row: 3, Status: application.ImportDuplicate, ID: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockQuoteStorage(ctrl)
+			importAll(mockStorage, false)
+			svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+			resp, err := svc.ImportQuotes(ctx, &application.ImportQuotesRequest{Format: tt.format, Body: strings.NewReader(tt.body)})
+			require.NoError(t, err)
+			require.Len(t, resp.Rows, len(tt.want))
+			for i, want := range tt.want {
+				got := resp.Rows[i]
+				assert.Equal(t, want.Row, got.Row)
+				assert.Equal(t, want.Status, got.Status)
+				assert.Equal(t, want.ID, got.ID)
+				if want.Status == application.ImportInvalid {
+					assert.NotEmpty(t, got.Error)
+				}
+				if want.Error != "" {
+					assert.Equal(t, want.Error, got.Error)
+				}
+			}
+		})
+	}
+
+	t.Run("batches and dry run", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStorage := mocks.NewMockQuoteStorage(ctrl)
+		var batches []int
+		mockStorage.EXPECT().
+			ImportQuotes(gomock.Any(), gomock.Any(), true).
+			DoAndReturn(func(_ context.Context, quotes []*storage.Quote, _ bool) ([]storage.ImportOutcome, error) {
+				batches = append(batches, len(quotes))
+				outcomes := make([]storage.ImportOutcome, len(quotes))
+				for i, q := range quotes {
+					outcomes[i] = storage.ImportOutcome{ContentHash: q.Quote}
+				}
+				return outcomes, nil
+			}).
+			Times(3)
+		svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+		var body strings.Builder
+		for i := 0; i < 1200; i++ {
+			fmt.Fprintf(&body, "{\"author\":\"A\",\"quote\":\"quote %d\"}\n", i)
+		}
+		resp, err := svc.ImportQuotes(ctx, &application.ImportQuotesRequest{
+			Format: application.ImportNDJSON,
+			Body:   strings.NewReader(body.String()),
+			DryRun: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{500, 500, 200}, batches)
+		assert.Equal(t, 1200, resp.Inserted)
+		assert.True(t, resp.DryRun)
+	})
+
+	t.Run("dry run duplicate across batches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// Like storage, each dry-run batch is rolled back: it only sees duplicates within itself.
+		mockStorage := mocks.NewMockQuoteStorage(ctrl)
+		mockStorage.EXPECT().
+			ImportQuotes(gomock.Any(), gomock.Any(), true).
+			DoAndReturn(func(_ context.Context, quotes []*storage.Quote, _ bool) ([]storage.ImportOutcome, error) {
+				outcomes := make([]storage.ImportOutcome, len(quotes))
+				batch := make(map[string]bool)
+				for i, q := range quotes {
+					hash := strings.ToLower(q.Quote)
+					outcomes[i] = storage.ImportOutcome{Duplicate: batch[hash], ContentHash: hash}
+					batch[hash] = true
+				}
+				return outcomes, nil
+			}).
+			Times(2)
+		svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+		var body strings.Builder
+		for i := 0; i < 500; i++ {
+			fmt.Fprintf(&body, "{\"author\":\"A\",\"quote\":\"quote %d\"}\n", i)
+		}
+		body.WriteString("{\"author\":\"A\",\"quote\":\"QUOTE 0\"}\n")
+		resp, err := svc.ImportQuotes(ctx, &application.ImportQuotesRequest{
+			Format: application.ImportNDJSON,
+			Body:   strings.NewReader(body.String()),
+			DryRun: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 500, resp.Inserted)
+		assert.Equal(t, 1, resp.Duplicates)
+		assert.Equal(t, application.ImportInserted, resp.Rows[0].Status)
+		assert.Equal(t, application.ImportDuplicate, resp.Rows[500].Status)
+	})
+
+	t.Run("bad input", func(t *testing.T) {
+		svc := &application.Service{Log: newTestLogger()}
+
+		_, err := svc.ImportQuotes(ctx, &application.ImportQuotesRequest{Format: application.ImportJSON, Body: strings.NewReader(`{"author":"A"}`)})
+		assert.ErrorIs(t, err, application.ErrValidation)
+		_, err = svc.ImportQuotes(ctx, &application.ImportQuotesRequest{Format: application.ImportCSV, Body: strings.NewReader("name,text\n")})
+		assert.ErrorIs(t, err, application.ErrValidation)
+		_, err = svc.ImportQuotes(contextAs("bob", application.RoleReader), &application.ImportQuotesRequest{Format: application.ImportJSON})
+		assert.ErrorIs(t, err, application.ErrForbidden)
+	})
+}
+
+func TestExportQuotes(t *testing.T) {
+	created := time.Date(2025, 6, 3, 13, 41, 42, 0, time.UTC)
+	stored := []*storage.Quote{
+		{ID: 2, Author: "Seneca", Quote: "Luck is what happens\nwhen preparation meets opportunity.", Lang: "en", Tags: []string{"luck", "stoic"}, CreatedAt: created, UpdatedAt: created},
+		{ID: 1, Author: "Confucius", Quote: "Life is simple, \"but\" complicated.", CreatedAt: created, UpdatedAt: created},
+	}
+
+	tests := []struct {
+		format application.ExportFormat
+		want   string
+	}{
+		{
+			format: application.ExportNDJSON,
+			want: `{"ID":2,"AuthorID":0,"Author":"Seneca","Quote":"Luck is what happens\nwhen preparation meets opportunity.","Lang":"en","CreatedBy":"","Version":0,"CreatedAt":"2025-06-03T13:41:42Z","UpdatedAt":"2025-06-03T13:41:42Z","Tags":["luck","stoic"]}
+{"ID":1,"AuthorID":0,"Author":"Confucius","Quote":"Life is simple, \"but\" complicated.","Lang":"","CreatedBy":"","Version":0,"CreatedAt":"2025-06-03T13:41:42Z","UpdatedAt":"2025-06-03T13:41:42Z","Tags":null}
+`,
+		},
+		{
+			format: application.ExportCSV,
+			want: `id,author,quote,lang,tags,created_by,created_at,updated_at
+2,Seneca,"Luck is what happens
+when preparation meets opportunity.",en,"luck,stoic",,2025-06-03T13:41:42Z,2025-06-03T13:41:42Z
+1,Confucius,"Life is simple, ""but"" complicated.",,,,2025-06-03T13:41:42Z,2025-06-03T13:41:42Z
+`,
+		},
+		{
+			format: application.ExportMarkdown,
+			want: "> Luck is what happens\n> when preparation meets opportunity.\n>\n> — Seneca\n\n`luck` `stoic`\n\n" +
+				"> Life is simple, \"but\" complicated.\n>\n> — Confucius\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockQuoteStorage(ctrl)
+			mockStorage.EXPECT().
+				ExportQuotes(gomock.Any(), storage.QuoteFilter{Author: "Seneca", Tags: []string{"stoic"}}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ storage.QuoteFilter, fn func(*storage.Quote) error) error {
+					for _, q := range stored {
+						if err := fn(q); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+			var out strings.Builder
+			err := svc.ExportQuotes(context.Background(), &application.ExportQuotesRequest{
+				Format: tt.format,
+				Author: " Seneca ",
+				Tags:   []string{"Stoic"},
+				Out:    &out,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStorage := mocks.NewMockQuoteStorage(ctrl)
+		mockStorage.EXPECT().
+			ExportQuotes(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ storage.QuoteFilter, fn func(*storage.Quote) error) error {
+				return fn(stored[0])
+			})
+		svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+		var out strings.Builder
+		err := svc.ExportQuotes(context.Background(), &application.ExportQuotesRequest{Format: application.ExportJSON, Out: &out})
+		require.NoError(t, err)
+		var quotes []application.Quote
+		require.NoError(t, json.Unmarshal([]byte(out.String()), &quotes))
+		require.Len(t, quotes, 1)
+		assert.Equal(t, "Seneca", quotes[0].Author)
+	})
+
+	t.Run("empty json", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStorage := mocks.NewMockQuoteStorage(ctrl)
+		mockStorage.EXPECT().ExportQuotes(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+		var out strings.Builder
+		err := svc.ExportQuotes(context.Background(), &application.ExportQuotesRequest{Format: application.ExportJSON, Out: &out})
+		require.NoError(t, err)
+		assert.JSONEq(t, "[]", out.String())
+	})
+
+	t.Run("storage error before the first quote", func(t *testing.T) {
+		for _, format := range []application.ExportFormat{application.ExportJSON, application.ExportCSV} {
+			ctrl := gomock.NewController(t)
+
+			mockStorage := mocks.NewMockQuoteStorage(ctrl)
+			mockStorage.EXPECT().
+				ExportQuotes(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(errors.New("failed to acquire connection"))
+			svc := &application.Service{DB: mockStorage, Log: newTestLogger()}
+
+			var out strings.Builder
+			err := svc.ExportQuotes(context.Background(), &application.ExportQuotesRequest{Format: format, Out: &out})
+			assert.Error(t, err)
+			assert.Empty(t, out.String(), format)
+			ctrl.Finish()
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		svc := &application.Service{Log: newTestLogger()}
+
+		var out strings.Builder
+		err := svc.ExportQuotes(context.Background(), &application.ExportQuotesRequest{Format: "xml", Out: &out})
+		assert.ErrorIs(t, err, application.ErrValidation)
+		assert.Empty(t, out.String())
+	})
+}
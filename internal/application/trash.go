@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetTrash lists deleted quotes. Editors and admins only.
+func (s *Service) GetTrash(ctx context.Context, req *GetTrashRequest) (*GetTrashResponse, error) {
+	if _, err := requireRole(ctx, RoleEditor, RoleAdmin); err != nil {
+		return nil, err
+	}
+	if req.Offset < 0 {
+		return nil, newValidationError("offset", "offset cannot be negative")
+	}
+
+	deleted, err := s.DB.GetDeletedQuotes(ctx, pageLimit(req.Limit), req.Offset)
+	if err != nil {
+		s.Log.Error("failed to get trash", "error", err)
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	quotes := make([]Quote, 0, len(deleted))
+	for _, q := range deleted {
+		quotes = append(quotes, toAppQuote(q))
+	}
+
+	return &GetTrashResponse{Quotes: quotes}, nil
+}
+
+// RestoreQuote takes a quote out of the trash. Like DeleteQuote, editors may
+// only restore the quotes they added. Restoring fails with ErrConflict when
+// the same text was added again after the deletion.
+func (s *Service) RestoreQuote(ctx context.Context, req *RestoreQuoteRequest) (*RestoreQuoteResponse, error) {
+	principal, err := requireRole(ctx, RoleEditor, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.ID == 0 {
+		return nil, newValidationError("id", "id parameter is required")
+	}
+
+	restored, err := s.DB.RestoreQuote(withActor(ctx, principal.Subject), req.ID, ownerOf(principal), req.IfVersions)
+	if err != nil {
+		if !isExpected(err) {
+			s.Log.Error("failed to restore quote", "id", req.ID, "error", err)
+		}
+		return nil, storageError("failed to restore quote", err)
+	}
+
+	return &RestoreQuoteResponse{Quote: toAppQuote(restored)}, nil
+}
+
+// PurgeTrash permanently removes quotes deleted more than Config.TrashRetention
+// ago. Without a positive retention it does nothing rather than empty the trash.
+func (s *Service) PurgeTrash(ctx context.Context) {
+	if s.Config == nil || s.Config.TrashRetention <= 0 {
+		return
+	}
+
+	purged, err := s.DB.PurgeDeletedQuotes(withActor(ctx, purgeActor), time.Now().Add(-s.Config.TrashRetention))
+	if err != nil {
+		s.Log.Error("failed to purge trash", "error", err)
+		return
+	}
+	if purged > 0 {
+		s.Log.Info("purged deleted quotes", "count", purged)
+	}
+}
@@ -1,5 +1,12 @@
 package rest
 
+import "time"
+
 type Config struct {
 	Port uint64 `env:"PORT" yaml:"port"`
+	// RequireIfMatch rejects updates and deletes without an If-Match header with 428.
+	RequireIfMatch bool `env:"REQUIRE_IF_MATCH" yaml:"require-if-match"`
+	// ShutdownDelay is how long the server keeps serving with a failing /readyz
+	// after a stop signal, before it stops accepting connections.
+	ShutdownDelay time.Duration `env:"SHUTDOWN_DELAY" envDefault:"0s" yaml:"shutdown-delay"`
 }
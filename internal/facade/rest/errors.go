@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/azaliaz/quote-service/internal/application"
+)
+
+const (
+	codeBadRequest           = "bad_request"
+	codeValidation           = "validation_failed"
+	codeUnauthorized         = "unauthorized"
+	codeForbidden            = "forbidden"
+	codeNotFound             = "not_found"
+	codeConflict             = "conflict"
+	codePreconditionFailed   = "precondition_failed"
+	codePreconditionRequired = "precondition_required"
+	codeMethodNotAllowed     = "method_not_allowed"
+	codeUnsupportedMedia     = "unsupported_media_type"
+	codeInternal             = "internal_error"
+)
+
+// errorResponse is the body of every non-2xx response.
+type errorResponse struct {
+	Code      string        `json:"code"`
+	Message   string        `json:"message"`
+	RequestID string        `json:"request_id,omitempty"`
+	Details   []errorDetail `json:"details,omitempty"`
+	// ExistingID points at the stored quote when a duplicate was rejected.
+	ExistingID int64 `json:"existing_id,omitempty"`
+}
+
+type errorDetail struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// statusFromError maps application errors onto HTTP status codes and error codes.
+func statusFromError(err error) (int, string) {
+	switch {
+	case errors.Is(err, application.ErrValidation):
+		return http.StatusBadRequest, codeValidation
+	case errors.Is(err, application.ErrUnauthorized):
+		return http.StatusUnauthorized, codeUnauthorized
+	case errors.Is(err, application.ErrForbidden):
+		return http.StatusForbidden, codeForbidden
+	case errors.Is(err, application.ErrNotFound):
+		return http.StatusNotFound, codeNotFound
+	case errors.Is(err, application.ErrConflict):
+		return http.StatusConflict, codeConflict
+	case errors.Is(err, application.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed, codePreconditionFailed
+	}
+	return http.StatusInternalServerError, codeInternal
+}
+
+// writeError sends err with the status it maps to. Client errors carry the
+// application message; server errors are logged and answered with message only,
+// so storage details never reach the client.
+func (api *Service) writeError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	status, code := statusFromError(err)
+	if status == http.StatusInternalServerError {
+		api.Log.Error(message, "error", err, "request_id", application.RequestIDFromContext(r.Context()))
+		api.writeErrorResponse(w, r, status, errorResponse{Code: code, Message: message})
+		return
+	}
+
+	resp := errorResponse{Code: code, Message: err.Error()}
+	var vErr *application.ValidationError
+	if errors.As(err, &vErr) {
+		resp.Message = vErr.Message
+		if vErr.Field != "" {
+			resp.Details = []errorDetail{{Field: vErr.Field, Message: vErr.Message}}
+		}
+	}
+	var dupErr *application.DuplicateQuoteError
+	if errors.As(err, &dupErr) {
+		resp.ExistingID = dupErr.ExistingID
+	}
+	api.writeErrorResponse(w, r, status, resp)
+}
+
+// writeStatusError sends an error that was detected by the handler itself.
+func (api *Service) writeStatusError(w http.ResponseWriter, r *http.Request, status int, code, message string, details ...errorDetail) {
+	api.writeErrorResponse(w, r, status, errorResponse{Code: code, Message: message, Details: details})
+}
+
+func (api *Service) writeErrorResponse(w http.ResponseWriter, r *http.Request, status int, resp errorResponse) {
+	resp.RequestID = application.RequestIDFromContext(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		api.Log.Error("failed to encode error response", "error", err)
+	}
+}
+
+func (api *Service) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	api.writeStatusError(w, r, http.StatusMethodNotAllowed, codeMethodNotAllowed, "Method not allowed")
+}
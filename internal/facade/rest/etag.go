@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var errInvalidETag = errors.New("invalid entity tag")
+
+// quoteETag is the strong entity tag of a quote at version.
+func quoteETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseQuoteETag returns the version encoded by a strong entity tag made by quoteETag.
+func parseQuoteETag(tag string) (int, error) {
+	unquoted, ok := strings.CutPrefix(tag, `"`)
+	if !ok {
+		return 0, errInvalidETag
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, errInvalidETag
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return 0, errInvalidETag
+	}
+	return version, nil
+}
+
+// ifMatchVersions returns the quote versions a write is conditional on, or nil
+// for an unconditional write. If-Match takes "*" or a list of entity tags, and
+// the write goes ahead when the quote is at any version the list names; weak
+// and foreign tags never match. A header no version can match is answered
+// with 412, a missing one with 428 when Config.RequireIfMatch is set. ok is
+// false when a response has been written.
+func (api *Service) ifMatchVersions(w http.ResponseWriter, r *http.Request) ([]int, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	switch header {
+	case "":
+		if api.Config != nil && api.Config.RequireIfMatch {
+			api.writeStatusError(w, r, http.StatusPreconditionRequired, codePreconditionRequired,
+				"If-Match header is required")
+			return nil, false
+		}
+		return nil, true
+	case "*":
+		return nil, true
+	}
+
+	var versions []int
+	for _, candidate := range strings.Split(header, ",") {
+		v, err := parseQuoteETag(strings.TrimSpace(candidate))
+		if err != nil || slices.Contains(versions, v) {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	if len(versions) == 0 {
+		api.writeStatusError(w, r, http.StatusPreconditionFailed, codePreconditionFailed,
+			"If-Match does not match the current version")
+		return nil, false
+	}
+	return versions, true
+}
+
+// noneMatch reports whether an If-None-Match header matches etag. Entity tags
+// are compared weakly, as RFC 9110 requires for If-None-Match.
+func noneMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
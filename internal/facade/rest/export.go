@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/azaliaz/quote-service/internal/application"
+)
+
+// exportType is the Content-Type and file extension of an export format.
+type exportType struct {
+	contentType string
+	extension   string
+}
+
+var exportTypes = map[application.ExportFormat]exportType{
+	application.ExportJSON:     {contentType: "application/json", extension: "json"},
+	application.ExportNDJSON:   {contentType: "application/x-ndjson", extension: "ndjson"},
+	application.ExportCSV:      {contentType: "text/csv; charset=utf-8", extension: "csv"},
+	application.ExportMarkdown: {contentType: "text/markdown; charset=utf-8", extension: "md"},
+}
+
+// HandleExport serves GET /quotes/export?format=json|ndjson|csv|markdown, streaming
+// the quotes matching the GET /quotes filters as a file download.
+func (api *Service) HandleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	format := application.ExportFormat(query.Get("format"))
+	if format == "" {
+		format = application.ExportJSON
+	}
+	typ, ok := exportTypes[format]
+	if !ok {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid format",
+			errorDetail{Field: "format", Message: "must be one of json, ndjson, csv, markdown"})
+		return
+	}
+
+	tags, matchAll, ok := api.tagFilter(w, r)
+	if !ok {
+		return
+	}
+
+	extendDeadlines(w)
+	out := &exportWriter{
+		ResponseWriter: w,
+		contentType:    typ.contentType,
+		filename:       fmt.Sprintf("quotes-%s.%s", time.Now().UTC().Format("2006-01-02"), typ.extension),
+	}
+	err := api.App.ExportQuotes(r.Context(), &application.ExportQuotesRequest{
+		Format:       format,
+		Author:       query.Get("author"),
+		Tags:         tags,
+		MatchAllTags: matchAll,
+		Out:          out,
+	})
+	if err == nil {
+		return
+	}
+	if !out.started {
+		api.writeError(w, r, err, "Failed to export quotes")
+		return
+	}
+
+	// The status is already sent: abort the connection so the client sees
+	// a truncated download instead of a seemingly complete file.
+	api.Log.Error("export interrupted", "error", err, "request_id", application.RequestIDFromContext(r.Context()))
+	panic(http.ErrAbortHandler)
+}
+
+// exportWriter sends the download headers with the first chunk of the body, so
+// errors raised before anything is written can still use the error envelope.
+type exportWriter struct {
+	http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		h := e.Header()
+		h.Set("Content-Type", e.contentType)
+		h.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+	}
+	return e.ResponseWriter.Write(p)
+}
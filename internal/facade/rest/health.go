@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/azaliaz/quote-service/pkg/service"
+)
+
+// States of the HTTP server, as reported by CheckHealth.
+const (
+	stateIdle int32 = iota
+	stateListening
+	stateStopping
+	stateFailed
+)
+
+const (
+	statusUp   = "up"
+	statusDown = "down"
+)
+
+type healthResponse struct {
+	Status       string                     `json:"status"`
+	ShuttingDown bool                       `json:"shutting_down,omitempty"`
+	Components   map[string]componentHealth `json:"components"`
+}
+
+type componentHealth struct {
+	Status  string         `json:"status"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// CheckHealth reports the state of the listener. The server is ready only
+// while it accepts connections and no shutdown has started.
+func (api *Service) CheckHealth(_ context.Context) service.Health {
+	health := service.Health{Name: "http", Live: true}
+	if api.Server != nil {
+		health.Details = map[string]any{"addr": api.Server.Addr}
+	}
+	switch api.state.Load() {
+	case stateIdle:
+		health.Error = "not listening yet"
+	case stateListening:
+		health.Ready = true
+	case stateStopping:
+		health.Error = "shutting down"
+	case stateFailed:
+		health.Live = false
+		health.Error = "listener failed"
+	}
+	return health
+}
+
+// HandleLiveness serves GET /healthz: 200 unless a component needs a restart.
+func (api *Service) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	api.handleHealth(w, r, false)
+}
+
+// HandleReadiness serves GET /readyz: 200 while every component can serve
+// requests, 503 otherwise and from the start of a graceful shutdown.
+func (api *Service) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	api.handleHealth(w, r, true)
+}
+
+// handleHealth writes the liveness report, or the readiness one when ready is set.
+func (api *Service) handleHealth(w http.ResponseWriter, r *http.Request, ready bool) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	report := api.healthReport(r.Context())
+	resp := healthResponse{
+		Status:       statusUp,
+		ShuttingDown: report.Stopping,
+		Components:   make(map[string]componentHealth, len(report.Components)),
+	}
+	status := http.StatusOK
+	if !report.Live || ready && !report.Ready {
+		resp.Status = statusDown
+		status = http.StatusServiceUnavailable
+	}
+	for _, c := range report.Components {
+		component := componentHealth{Status: statusUp, Error: c.Error, Details: c.Details}
+		if !c.Live || ready && !c.Ready {
+			component.Status = statusDown
+		}
+		resp.Components[c.Name] = component
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(resp); err != nil {
+		api.Log.Error("failed to encode health response", "error", err)
+	}
+}
+
+func (api *Service) healthReport(ctx context.Context) service.HealthReport {
+	if api.Health != nil {
+		return api.Health.Health(ctx)
+	}
+	return service.NewHealthReport(api.state.Load() == stateStopping, api.CheckHealth(ctx))
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/azaliaz/quote-service/internal/application"
+)
+
+// importFormats maps the accepted Content-Types of POST /quotes/import onto import formats.
+var importFormats = map[string]application.ImportFormat{
+	"application/json":     application.ImportJSON,
+	"application/x-ndjson": application.ImportNDJSON,
+	"application/ndjson":   application.ImportNDJSON,
+	"application/jsonl":    application.ImportNDJSON,
+	"text/csv":             application.ImportCSV,
+}
+
+// HandleImport serves POST /quotes/import. The body is streamed into the
+// application, which validates and stores it batch by batch.
+func (api *Service) HandleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	format, ok := importFormats[mediaType]
+	if err != nil || !ok {
+		api.writeStatusError(w, r, http.StatusUnsupportedMediaType, codeUnsupportedMedia,
+			"Content-Type must be application/json, application/x-ndjson or text/csv")
+		return
+	}
+
+	var dryRun bool
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid dry_run",
+				errorDetail{Field: "dry_run", Message: "must be a boolean"})
+			return
+		}
+	}
+
+	extendDeadlines(w)
+	resp, err := api.App.ImportQuotes(r.Context(), &application.ImportQuotesRequest{
+		Format: format,
+		Body:   r.Body,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to import quotes")
+		return
+	}
+
+	api.writeJSON(w, r, resp)
+}
+
+// extendDeadlines lets a streaming request outlive the server read and write timeouts.
+func extendDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(streamTimeout)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+}
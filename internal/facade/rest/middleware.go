@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/azaliaz/quote-service/internal/application"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+
+	bearerPrefix = "Bearer "
+)
+
+// withRequestID takes the caller's X-Request-ID or generates one, echoes it in
+// the response and stores it in the request context.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(application.WithRequestID(r.Context(), id)))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// withAuth requires a valid bearer token on requests that modify data and
+// stores the authenticated principal in the request context. Reads stay
+// anonymous, but a token sent with them is still verified.
+func (api *Service) withAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			if isWriteMethod(r.Method) {
+				api.unauthorized(w, r, "Authentication required")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, bearerPrefix)
+		if !ok || token == "" {
+			api.unauthorized(w, r, "Invalid Authorization header")
+			return
+		}
+
+		resp, err := api.App.Authenticate(r.Context(), &application.AuthenticateRequest{Token: token})
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			api.writeError(w, r, err, "Failed to authenticate")
+			return
+		}
+
+		principal := resp.Principal
+		next.ServeHTTP(w, r.WithContext(application.WithPrincipal(r.Context(), &principal)))
+	})
+}
+
+func (api *Service) unauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	api.writeStatusError(w, r, http.StatusUnauthorized, codeUnauthorized, message)
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
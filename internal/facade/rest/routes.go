@@ -1,7 +1,9 @@
 package rest
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"github.com/azaliaz/quote-service/internal/application"
 	"net/http"
 	"strconv"
@@ -10,6 +12,9 @@ import (
 
 const (
 	expectedPartsLength = 3
+
+	tagModeAny = "any"
+	tagModeAll = "all"
 )
 
 func (api *Service) HandleQuotes(w http.ResponseWriter, r *http.Request) {
@@ -19,104 +24,698 @@ func (api *Service) HandleQuotes(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		api.AddQuote(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		api.methodNotAllowed(w, r)
 	}
 }
 
+// HandleRandomQuote returns a random quote, optionally restricted by ?author=,
+// ?tag= (with tag_mode) and ?lang=. 404 means no quote matches.
 func (api *Service) HandleRandomQuote(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	tags, matchAll, ok := api.tagFilter(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := api.App.GetRandomQuote(r.Context(), &application.GetRandomQuoteRequest{
+		Author:       query.Get("author"),
+		Tags:         tags,
+		MatchAllTags: matchAll,
+		Lang:         query.Get("lang"),
+	})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to get random quote")
+		return
+	}
+
+	api.writeJSON(w, r, resp.Quote)
+}
+
+// HandleDailyQuote returns the quote of the day, or of a past day with ?date=YYYY-MM-DD.
+func (api *Service) HandleDailyQuote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	resp, err := api.App.GetDailyQuote(r.Context(), &application.GetDailyQuoteRequest{
+		Date: r.URL.Query().Get("date"),
+	})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to get daily quote")
+		return
+	}
+
+	api.writeJSON(w, r, dailyQuote{Date: resp.Date, Quote: resp.Quote})
+}
+
+func (api *Service) HandleSearchQuotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "q parameter is required",
+			errorDetail{Field: "q", Message: "is required"})
+		return
+	}
+
+	limit, err := parseNonNegativeInt(query.Get("limit"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid limit",
+			errorDetail{Field: "limit", Message: err.Error()})
+		return
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid offset",
+			errorDetail{Field: "offset", Message: err.Error()})
+		return
+	}
+
+	resp, err := api.App.SearchQuotes(r.Context(), &application.SearchQuotesRequest{
+		Query:  q,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to search quotes")
+		return
+	}
+
+	api.writeJSON(w, r, searchResults{Results: resp.Results})
+}
+
+func (api *Service) HandleTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
 		return
 	}
 
-	resp, err := api.App.GetRandomQuote(r.Context(), &application.GetRandomQuoteRequest{})
+	resp, err := api.App.GetTags(r.Context(), &application.GetTagsRequest{})
 	if err != nil {
-		http.Error(w, "Failed to get random quote: "+err.Error(), http.StatusInternalServerError)
+		api.writeError(w, r, err, "Failed to get tags")
 		return
 	}
 
-	writeJSON(w, resp.Quote)
+	api.writeJSON(w, r, tagsList{Tags: resp.Tags})
 }
 
+func (api *Service) HandleAuthors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid limit",
+			errorDetail{Field: "limit", Message: err.Error()})
+		return
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid offset",
+			errorDetail{Field: "offset", Message: err.Error()})
+		return
+	}
+
+	resp, err := api.App.GetAuthors(r.Context(), &application.GetAuthorsRequest{Limit: limit, Offset: offset})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to get authors")
+		return
+	}
+
+	api.writeJSON(w, r, authorsList{Authors: resp.Authors})
+}
+
+func (api *Service) HandleTrash(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid limit",
+			errorDetail{Field: "limit", Message: err.Error()})
+		return
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid offset",
+			errorDetail{Field: "offset", Message: err.Error()})
+		return
+	}
+
+	resp, err := api.App.GetTrash(r.Context(), &application.GetTrashRequest{Limit: limit, Offset: offset})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to get trash")
+		return
+	}
+
+	api.writeJSON(w, r, trashList{Quotes: resp.Quotes})
+}
+
+// HandleAudit serves GET /audit, filtered by the optional quote_id, actor and since parameters.
+func (api *Service) HandleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid limit",
+			errorDetail{Field: "limit", Message: err.Error()})
+		return
+	}
+	offset, err := parseNonNegativeInt(query.Get("offset"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid offset",
+			errorDetail{Field: "offset", Message: err.Error()})
+		return
+	}
+	var quoteID int64
+	if raw := query.Get("quote_id"); raw != "" {
+		if quoteID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid quote_id",
+				errorDetail{Field: "quote_id", Message: "must be an integer"})
+			return
+		}
+	}
+
+	resp, err := api.App.GetAuditEvents(r.Context(), &application.GetAuditEventsRequest{
+		QuoteID: quoteID,
+		Actor:   query.Get("actor"),
+		Since:   query.Get("since"),
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to get audit events")
+		return
+	}
+
+	api.writeJSON(w, r, auditList{Events: resp.Events})
+}
+
+// HandleAuthorByID serves /authors/{id} and /authors/{id}/quotes.
+func (api *Service) HandleAuthorByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		api.methodNotAllowed(w, r)
+		return
+	}
+
+	parts := strings.Split(r.URL.Path, "/")
+	switch {
+	case len(parts) == expectedPartsLength:
+	case len(parts) == expectedPartsLength+1 && parts[3] == "quotes":
+	default:
+		api.writeStatusError(w, r, http.StatusNotFound, codeNotFound, "Not found")
+		return
+	}
+
+	id, ok := api.parseID(w, r, parts[2], "Invalid author ID")
+	if !ok {
+		return
+	}
+
+	if len(parts) == expectedPartsLength {
+		api.GetAuthor(w, r, id)
+		return
+	}
+	api.GetAuthorQuotes(w, r, id)
+}
+
+func (api *Service) GetAuthor(w http.ResponseWriter, r *http.Request, id int64) {
+	resp, err := api.App.GetAuthor(r.Context(), &application.GetAuthorRequest{ID: id})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to get author")
+		return
+	}
+
+	api.writeJSON(w, r, resp.Author)
+}
+
+func (api *Service) GetAuthorQuotes(w http.ResponseWriter, r *http.Request, id int64) {
+	query := r.URL.Query()
+	limit, err := parseNonNegativeInt(query.Get("limit"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid limit",
+			errorDetail{Field: "limit", Message: err.Error()})
+		return
+	}
+
+	resp, err := api.App.GetAuthorQuotes(r.Context(), &application.GetAuthorQuotesRequest{
+		ID:     id,
+		Limit:  limit,
+		Cursor: query.Get("cursor"),
+	})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to get author quotes")
+		return
+	}
+
+	api.writeJSON(w, r, quotesPage{Quotes: resp.Quotes, NextCursor: resp.NextCursor})
+}
+
+// HandleQuoteByID serves /quotes/{id} and its sub-resources: POST /quotes/{id}/restore,
+// GET /quotes/{id}/revisions, GET /quotes/{id}/revisions/{rev}/diff and
+// POST /quotes/{id}/revisions/{rev}/restore.
 func (api *Service) HandleQuoteByID(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	parts := strings.Split(r.URL.Path, "/")
+	switch {
+	case len(parts) == expectedPartsLength+1 && parts[3] == "restore":
+		if id, ok := api.subresourceID(w, r, parts, http.MethodPost); ok {
+			api.RestoreQuote(w, r, id)
+		}
+		return
+	case len(parts) == expectedPartsLength+1 && parts[3] == "revisions":
+		if id, ok := api.subresourceID(w, r, parts, http.MethodGet); ok {
+			api.GetQuoteRevisions(w, r, id)
+		}
+		return
+	case len(parts) == expectedPartsLength+3 && parts[3] == "revisions" && parts[5] == "diff":
+		id, ok := api.subresourceID(w, r, parts, http.MethodGet)
+		if !ok {
+			return
+		}
+		if revision, ok := api.parseRevision(w, r, parts[4]); ok {
+			api.DiffQuoteRevisions(w, r, id, revision)
+		}
 		return
+	case len(parts) == expectedPartsLength+3 && parts[3] == "revisions" && parts[5] == "restore":
+		id, ok := api.subresourceID(w, r, parts, http.MethodPost)
+		if !ok {
+			return
+		}
+		if revision, ok := api.parseRevision(w, r, parts[4]); ok {
+			api.RestoreQuoteRevision(w, r, id, revision)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		api.GetQuote(w, r)
+	case http.MethodDelete:
+		api.DeleteQuote(w, r)
+	case http.MethodPut:
+		api.UpdateQuote(w, r, true)
+	case http.MethodPatch:
+		api.UpdateQuote(w, r, false)
+	default:
+		api.methodNotAllowed(w, r)
+	}
+}
+
+// subresourceID checks the method of a request to a /quotes/{id}/... path and
+// parses its quote id, writing an error response when either is wrong.
+func (api *Service) subresourceID(w http.ResponseWriter, r *http.Request, parts []string, method string) (int64, bool) {
+	if r.Method != method {
+		api.methodNotAllowed(w, r)
+		return 0, false
 	}
+	return api.parseID(w, r, parts[2], "Invalid quote ID")
+}
 
+// quoteIDFromPath extracts the id from /quotes/{id}, writing a 400 response when it is malformed.
+func (api *Service) quoteIDFromPath(w http.ResponseWriter, r *http.Request) (int64, bool) {
 	parts := strings.Split(r.URL.Path, "/")
 	if len(parts) != expectedPartsLength {
-		http.Error(w, "Invalid URL", http.StatusBadRequest)
+		api.writeStatusError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid URL")
+		return 0, false
+	}
+
+	return api.parseID(w, r, parts[2], "Invalid quote ID")
+}
+
+// parseID parses a path id segment, writing a 400 response with message when it is not an integer.
+func (api *Service) parseID(w http.ResponseWriter, r *http.Request, raw, message string) (int64, bool) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, message,
+			errorDetail{Field: "id", Message: "must be an integer"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (api *Service) GetQuote(w http.ResponseWriter, r *http.Request) {
+	id, ok := api.quoteIDFromPath(w, r)
+	if !ok {
 		return
 	}
 
-	id, err := strconv.ParseInt(parts[2], 10, 64)
+	resp, err := api.App.GetQuote(r.Context(), &application.GetQuoteRequest{ID: id})
 	if err != nil {
-		http.Error(w, "Invalid quote ID", http.StatusBadRequest)
+		api.writeError(w, r, err, "Failed to get quote")
+		return
+	}
+
+	etag := quoteETag(resp.Quote.Version)
+	w.Header().Set("ETag", etag)
+	if header := r.Header.Get("If-None-Match"); header != "" && noneMatch(header, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	api.writeJSON(w, r, resp.Quote)
+}
+
+func (api *Service) DeleteQuote(w http.ResponseWriter, r *http.Request) {
+	id, ok := api.quoteIDFromPath(w, r)
+	if !ok {
 		return
 	}
 
-	resp, err := api.App.DeleteQuote(r.Context(), &application.DeleteQuoteRequest{ID: id})
+	versions, ok := api.ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := api.App.DeleteQuote(r.Context(), &application.DeleteQuoteRequest{ID: id, IfVersions: versions})
 	if err != nil {
-		http.Error(w, "Failed to delete quote: "+err.Error(), http.StatusInternalServerError)
+		api.writeError(w, r, err, "Failed to delete quote")
 		return
 	}
 
 	if !resp.Success {
-		http.Error(w, "Quote not found", http.StatusNotFound)
+		api.writeStatusError(w, r, http.StatusNotFound, codeNotFound, "Quote not found")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (api *Service) RestoreQuote(w http.ResponseWriter, r *http.Request, id int64) {
+	versions, ok := api.ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := api.App.RestoreQuote(r.Context(), &application.RestoreQuoteRequest{ID: id, IfVersions: versions})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to restore quote")
+		return
+	}
+
+	w.Header().Set("ETag", quoteETag(resp.Quote.Version))
+	api.writeJSON(w, r, resp.Quote)
+}
+
+// parseRevision parses a revision number from the path, writing an error response when it is not one.
+func (api *Service) parseRevision(w http.ResponseWriter, r *http.Request, raw string) (int, bool) {
+	revision, err := strconv.Atoi(raw)
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid revision",
+			errorDetail{Field: "revision", Message: "must be an integer"})
+		return 0, false
+	}
+	return revision, true
+}
+
+func (api *Service) GetQuoteRevisions(w http.ResponseWriter, r *http.Request, id int64) {
+	resp, err := api.App.GetQuoteRevisions(r.Context(), &application.GetQuoteRevisionsRequest{ID: id})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to get quote revisions")
+		return
+	}
+
+	api.writeJSON(w, r, revisionsList{Revisions: resp.Revisions})
+}
+
+// DiffQuoteRevisions compares a revision with ?against=, the previous revision by default.
+func (api *Service) DiffQuoteRevisions(w http.ResponseWriter, r *http.Request, id int64, revision int) {
+	var against int
+	if raw := r.URL.Query().Get("against"); raw != "" {
+		var err error
+		if against, err = strconv.Atoi(raw); err != nil || against <= 0 {
+			api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid against",
+				errorDetail{Field: "against", Message: "must be a positive integer"})
+			return
+		}
+	}
+
+	resp, err := api.App.DiffQuoteRevisions(r.Context(), &application.DiffQuoteRevisionsRequest{ID: id, From: against, To: revision})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to diff quote revisions")
+		return
+	}
+
+	api.writeJSON(w, r, resp.Diff)
+}
+
+func (api *Service) RestoreQuoteRevision(w http.ResponseWriter, r *http.Request, id int64, revision int) {
+	versions, ok := api.ifMatchVersions(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := api.App.RestoreQuoteRevision(r.Context(),
+		&application.RestoreQuoteRevisionRequest{ID: id, Revision: revision, IfVersions: versions})
+	if err != nil {
+		api.writeError(w, r, err, "Failed to restore quote revision")
+		return
+	}
+
+	w.Header().Set("ETag", quoteETag(resp.Quote.Version))
+	api.writeJSON(w, r, resp.Quote)
+}
+
+// UpdateQuote handles PUT (replace set) and PATCH requests for a single quote.
+func (api *Service) UpdateQuote(w http.ResponseWriter, r *http.Request, replace bool) {
+	id, ok := api.quoteIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req application.UpdateQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid JSON body")
+		return
+	}
+	req.ID = id
+	req.Replace = replace
+	if req.IfVersions, ok = api.ifMatchVersions(w, r); !ok {
+		return
+	}
+
+	if replace {
+		if details := requiredFields(req.Author, req.Quote); len(details) > 0 {
+			api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "author and quote fields are required", details...)
+			return
+		}
+	}
+	if req.Author == nil && req.Quote == nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "author or quote field is required")
+		return
+	}
+	if details := nonEmptyFields(req.Author, req.Quote); len(details) > 0 {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "author and quote fields cannot be empty", details...)
+		return
+	}
+
+	resp, err := api.App.UpdateQuote(r.Context(), &req)
+	if err != nil {
+		api.writeError(w, r, err, "Failed to update quote")
+		return
+	}
+
+	w.Header().Set("ETag", quoteETag(resp.Quote.Version))
+	api.writeJSON(w, r, resp.Quote)
+}
+
+// addedQuote is the body of a POST /quotes response, the same for a new quote
+// and an upserted duplicate.
+type addedQuote struct {
+	ID      int64 `json:"id"`
+	Created bool  `json:"created"`
+}
+
+// AddQuote creates a quote. With ?upsert=true a duplicate is answered with the
+// id and ETag of the stored quote and created set to false instead of 409.
 func (api *Service) AddQuote(w http.ResponseWriter, r *http.Request) {
 	var req application.AddQuoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		api.writeStatusError(w, r, http.StatusBadRequest, codeBadRequest, "Invalid JSON body")
 		return
 	}
-	if req.Author == "" || req.Quote == "" {
-		http.Error(w, "author and quote fields are required", http.StatusBadRequest)
+	if details := requiredFields(&req.Author, &req.Quote); len(details) > 0 {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "author and quote fields are required", details...)
 		return
 	}
+	if raw := r.URL.Query().Get("upsert"); raw != "" {
+		upsert, err := strconv.ParseBool(raw)
+		if err != nil {
+			api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid upsert",
+				errorDetail{Field: "upsert", Message: "must be a boolean"})
+			return
+		}
+		req.Upsert = upsert
+	}
 
 	resp, err := api.App.AddQuote(r.Context(), &req)
 	if err != nil {
-		http.Error(w, "Failed to add quote: "+err.Error(), http.StatusInternalServerError)
+		api.writeError(w, r, err, "Failed to add quote")
 		return
 	}
 
-	writeJSON(w, map[string]int64{"id": resp.ID})
+	w.Header().Set("ETag", quoteETag(resp.Version))
+	api.writeJSON(w, r, addedQuote{ID: resp.ID, Created: resp.Created})
+}
+
+type quotesPage struct {
+	Quotes     []application.Quote `json:"quotes"`
+	NextCursor string              `json:"next_cursor,omitempty"`
 }
 
+// GetQuotes lists quotes. ?author= and ?tag= filters are combined with AND;
+// several tags match any of them unless tag_mode=all is given.
 func (api *Service) GetQuotes(w http.ResponseWriter, r *http.Request) {
-	author := r.URL.Query().Get("author")
+	query := r.URL.Query()
+	author := query.Get("author")
+	cursor := query.Get("cursor")
+
+	limit, err := parseNonNegativeInt(query.Get("limit"))
+	if err != nil {
+		api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid limit",
+			errorDetail{Field: "limit", Message: err.Error()})
+		return
+	}
 
-	if author != "" {
-		resp, err := api.App.GetQuotesByAuthor(r.Context(), &application.GetQuotesByAuthorRequest{Author: author})
+	tags, matchAll, ok := api.tagFilter(w, r)
+	if !ok {
+		return
+	}
+
+	if author != "" && len(tags) == 0 {
+		resp, err := api.App.GetQuotesByAuthor(r.Context(), &application.GetQuotesByAuthorRequest{
+			Author: author,
+			Limit:  limit,
+			Cursor: cursor,
+		})
 		if err != nil {
-			http.Error(w, "Failed to get quotes by author: "+err.Error(), http.StatusInternalServerError)
+			api.writeError(w, r, err, "Failed to get quotes by author")
 			return
 		}
-		writeJSON(w, resp.Quotes)
+		api.writeJSON(w, r, quotesPage{Quotes: resp.Quotes, NextCursor: resp.NextCursor})
 		return
 	}
 
-	resp, err := api.App.GetQuotes(r.Context(), &application.GetQuotesRequest{})
+	resp, err := api.App.GetQuotes(r.Context(), &application.GetQuotesRequest{
+		Author:       author,
+		Tags:         tags,
+		MatchAllTags: matchAll,
+		Limit:        limit,
+		Cursor:       cursor,
+	})
 	if err != nil {
-		http.Error(w, "Failed to get quotes: "+err.Error(), http.StatusInternalServerError)
+		api.writeError(w, r, err, "Failed to get quotes")
 		return
 	}
 
-	writeJSON(w, resp.Quotes)
+	api.writeJSON(w, r, quotesPage{Quotes: resp.Quotes, NextCursor: resp.NextCursor})
 }
 
-func writeJSON(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	enc := json.NewEncoder(w)
+// tagFilter reads the repeated ?tag= parameter and ?tag_mode=, writing a 400
+// response when tag_mode is unknown.
+func (api *Service) tagFilter(w http.ResponseWriter, r *http.Request) ([]string, bool, bool) {
+	query := r.URL.Query()
+	switch query.Get("tag_mode") {
+	case "", tagModeAny:
+		return query["tag"], false, true
+	case tagModeAll:
+		return query["tag"], true, true
+	}
+	api.writeStatusError(w, r, http.StatusBadRequest, codeValidation, "Invalid tag_mode",
+		errorDetail{Field: "tag_mode", Message: "must be one of: any, all"})
+	return nil, false, false
+}
+
+// requiredFields reports author and quote when they are missing or empty.
+func requiredFields(author, quote *string) []errorDetail {
+	var details []errorDetail
+	if author == nil || *author == "" {
+		details = append(details, errorDetail{Field: "author", Message: "is required"})
+	}
+	if quote == nil || *quote == "" {
+		details = append(details, errorDetail{Field: "quote", Message: "is required"})
+	}
+	return details
+}
+
+// nonEmptyFields reports author and quote when they are present but empty.
+func nonEmptyFields(author, quote *string) []errorDetail {
+	var details []errorDetail
+	if author != nil && *author == "" {
+		details = append(details, errorDetail{Field: "author", Message: "cannot be empty"})
+	}
+	if quote != nil && *quote == "" {
+		details = append(details, errorDetail{Field: "quote", Message: "cannot be empty"})
+	}
+	return details
+}
+
+type dailyQuote struct {
+	Date  string            `json:"date"`
+	Quote application.Quote `json:"quote"`
+}
+
+type tagsList struct {
+	Tags []application.Tag `json:"tags"`
+}
+
+type trashList struct {
+	Quotes []application.Quote `json:"quotes"`
+}
+
+type revisionsList struct {
+	Revisions []application.QuoteRevision `json:"revisions"`
+}
+
+type auditList struct {
+	Events []application.AuditEvent `json:"events"`
+}
+
+type authorsList struct {
+	Authors []application.Author `json:"authors"`
+}
+
+type searchResults struct {
+	Results []application.SearchResult `json:"results"`
+}
+
+func parseNonNegativeInt(raw string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 0 {
+		return 0, errors.New("must be a non-negative integer")
+	}
+	return limit, nil
+}
+
+func (api *Service) writeJSON(w http.ResponseWriter, r *http.Request, data interface{}) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
 	enc.SetIndent("", "  ")
 	if err := enc.Encode(data); err != nil {
-		http.Error(w, "Failed to encode JSON: "+err.Error(), http.StatusInternalServerError)
+		api.writeError(w, r, err, "Failed to encode JSON")
+		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(buf.Bytes())
 }
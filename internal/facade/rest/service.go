@@ -2,10 +2,14 @@ package rest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/azaliaz/quote-service/internal/application"
+	"github.com/azaliaz/quote-service/pkg/service"
 	"log/slog"
+	"net"
 	"net/http"
+	"sync/atomic"
 
 	"time"
 )
@@ -15,6 +19,8 @@ const (
 	writeTimeout   = 10 * time.Second
 	idleTimeout    = 60 * time.Second
 	contextTimeout = 5 * time.Second
+	// streamTimeout replaces the read and write timeouts for streaming imports and exports.
+	streamTimeout = 10 * time.Minute
 )
 
 type Service struct {
@@ -22,6 +28,11 @@ type Service struct {
 	Config *Config
 	App    application.QuoteService // с заглавной буквы
 	Server *http.Server
+	// Health reports the components behind /healthz and /readyz; without it
+	// only the HTTP server itself is checked.
+	Health service.HealthReporter
+
+	state atomic.Int32
 }
 
 func NewAPI(logEntry *slog.Logger, config *Config, app application.QuoteService) *Service {
@@ -37,11 +48,22 @@ func (api *Service) Init() error {
 
 	mux.HandleFunc("/quotes", api.HandleQuotes)
 	mux.HandleFunc("/quotes/random", api.HandleRandomQuote)
+	mux.HandleFunc("/quotes/search", api.HandleSearchQuotes)
+	mux.HandleFunc("/quotes/daily", api.HandleDailyQuote)
+	mux.HandleFunc("/quotes/trash", api.HandleTrash)
+	mux.HandleFunc("/quotes/import", api.HandleImport)
+	mux.HandleFunc("/quotes/export", api.HandleExport)
 	mux.HandleFunc("/quotes/", api.HandleQuoteByID)
+	mux.HandleFunc("/tags", api.HandleTags)
+	mux.HandleFunc("/authors", api.HandleAuthors)
+	mux.HandleFunc("/authors/", api.HandleAuthorByID)
+	mux.HandleFunc("/audit", api.HandleAudit)
+	mux.HandleFunc("/healthz", api.HandleLiveness)
+	mux.HandleFunc("/readyz", api.HandleReadiness)
 	addr := fmt.Sprintf(":%d", api.Config.Port)
 	api.Server = &http.Server{
 		Addr:         addr,
-		Handler:      mux,
+		Handler:      withRequestID(api.withAuth(mux)),
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
 		IdleTimeout:  idleTimeout,
@@ -53,12 +75,27 @@ func (api *Service) Init() error {
 
 func (api *Service) Run(ctx context.Context) {
 	api.Log.Info("starting HTTP server", "addr", api.Server.Addr)
-	if err := api.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	ln, err := net.Listen("tcp", api.Server.Addr)
+	if err != nil {
+		api.state.Store(stateFailed)
+		api.Log.Error("HTTP server error", "error", err)
+		return
+	}
+	api.state.CompareAndSwap(stateIdle, stateListening)
+	if err := api.Server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		api.state.Store(stateFailed)
 		api.Log.Error("HTTP server error", "error", err)
 	}
 }
 
+// Stop fails readiness first and keeps serving for Config.ShutdownDelay, so
+// load balancers stop sending requests before the listener closes.
 func (api *Service) Stop() {
+	api.state.Store(stateStopping)
+	if api.Config != nil && api.Config.ShutdownDelay > 0 {
+		api.Log.Info("draining HTTP server", "delay", api.Config.ShutdownDelay)
+		time.Sleep(api.Config.ShutdownDelay)
+	}
 	api.Log.Info("stopping HTTP server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
@@ -1,16 +1,23 @@
 package tests
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azaliaz/quote-service/internal/application"
 	"github.com/azaliaz/quote-service/internal/application/mocks"
 	"github.com/azaliaz/quote-service/internal/facade/rest"
+	storagemocks "github.com/azaliaz/quote-service/internal/storage/mocks"
+	"github.com/azaliaz/quote-service/pkg/service"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,7 +26,7 @@ import (
 func newTestAPI(t *testing.T) (*rest.Service, *mocks.MockQuoteService) {
 	ctrl := gomock.NewController(t)
 	mockSvc := mocks.NewMockQuoteService(ctrl)
-	api := &rest.Service{App: mockSvc}
+	api := &rest.Service{App: mockSvc, Log: slog.New(slog.NewTextHandler(io.Discard, nil))}
 	t.Cleanup(ctrl.Finish)
 	return api, mockSvc
 }
@@ -45,7 +52,7 @@ func TestHandleQuotes(t *testing.T) {
 		reqBody := `{"author":"Author","quote":"Quote"}`
 		mockSvc.EXPECT().
 			AddQuote(gomock.Any(), &application.AddQuoteRequest{Author: "Author", Quote: "Quote"}).
-			Return(&application.AddQuoteResponse{ID: 42}, nil)
+			Return(&application.AddQuoteResponse{ID: 42, Created: true, Version: 1}, nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(reqBody))
 		req.Header.Set("Content-Type", "application/json")
@@ -54,10 +61,8 @@ func TestHandleQuotes(t *testing.T) {
 		api.HandleQuotes(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code)
-		var resp map[string]int64
-		err := json.NewDecoder(rr.Body).Decode(&resp)
-		require.NoError(t, err)
-		assert.Equal(t, int64(42), resp["id"])
+		assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+		assert.JSONEq(t, `{"id":42,"created":true}`, rr.Body.String())
 	})
 
 	t.Run("Unsupported method", func(t *testing.T) {
@@ -89,6 +94,50 @@ func TestHandleRandomQuote(t *testing.T) {
 		assert.Contains(t, rr.Body.String(), `"Author": "A"`)
 	})
 
+	t.Run("Empty table", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetRandomQuote(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("failed to get random quote: %w", application.ErrNotFound))
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/random", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleRandomQuote(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("With filters", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetRandomQuote(gomock.Any(), &application.GetRandomQuoteRequest{
+				Author:       "Seneca",
+				Tags:         []string{"motivation", "time"},
+				MatchAllTags: true,
+				Lang:         "en",
+			}).
+			Return(&application.GetRandomQuoteResponse{
+				Quote: application.Quote{ID: 2, Author: "Seneca", Quote: "Q", Lang: "en"},
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/quotes/random?author=Seneca&tag=motivation&tag=time&tag_mode=all&lang=en", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleRandomQuote(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"Author": "Seneca"`)
+	})
+
+	t.Run("Invalid tag_mode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quotes/random?tag=x&tag_mode=some", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleRandomQuote(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
 	t.Run("Non-GET method", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPost, "/quotes/random", nil)
 		rr := httptest.NewRecorder()
@@ -129,12 +178,105 @@ func TestHandleQuoteByID(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, rr.Code)
 	})
 
-	t.Run("Wrong method", func(t *testing.T) {
+	t.Run("PUT /quotes/{id} success", func(t *testing.T) {
+		author, text := "Author", "Fixed quote"
+		mockSvc.EXPECT().
+			UpdateQuote(gomock.Any(), &application.UpdateQuoteRequest{ID: 123, Author: &author, Quote: &text, Replace: true}).
+			Return(&application.UpdateQuoteResponse{
+				Quote: application.Quote{ID: 123, Author: author, Quote: text},
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/quotes/123", strings.NewReader(`{"author":"Author","quote":"Fixed quote"}`))
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"Quote": "Fixed quote"`)
+	})
+
+	t.Run("PUT /quotes/{id} missing field", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/quotes/123", strings.NewReader(`{"quote":"Only text"}`))
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("PATCH /quotes/{id} success", func(t *testing.T) {
+		text := "Fixed quote"
+		mockSvc.EXPECT().
+			UpdateQuote(gomock.Any(), &application.UpdateQuoteRequest{ID: 123, Quote: &text}).
+			Return(&application.UpdateQuoteResponse{
+				Quote: application.Quote{ID: 123, Author: "Author", Quote: text},
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/quotes/123", strings.NewReader(`{"quote":"Fixed quote"}`))
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("PATCH /quotes/{id} not found", func(t *testing.T) {
+		mockSvc.EXPECT().
+			UpdateQuote(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("failed to update quote: %w", application.ErrNotFound))
+
+		req := httptest.NewRequest(http.MethodPatch, "/quotes/123", strings.NewReader(`{"author":"Author"}`))
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("PATCH /quotes/{id} empty body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/quotes/123", strings.NewReader(`{}`))
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("GET /quotes/{id} success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuote(gomock.Any(), &application.GetQuoteRequest{ID: 123}).
+			Return(&application.GetQuoteResponse{
+				Quote: application.Quote{ID: 123, Author: "Author", Quote: "Quote"},
+			}, nil)
+
 		req := httptest.NewRequest(http.MethodGet, "/quotes/123", nil)
 		rr := httptest.NewRecorder()
 
 		api.HandleQuoteByID(rr, req)
 
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"ID": 123`)
+	})
+
+	t.Run("GET /quotes/{id} not found", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuote(gomock.Any(), &application.GetQuoteRequest{ID: 404}).
+			Return(nil, fmt.Errorf("failed to get quote: %w", application.ErrNotFound))
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/404", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Wrong method", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/123", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
 		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
 	})
 
@@ -171,6 +313,19 @@ func TestHandleQuoteByID(t *testing.T) {
 		assert.Contains(t, rr.Body.String(), "Failed to delete quote")
 	})
 
+	t.Run("Delete missing quote", func(t *testing.T) {
+		mockSvc.EXPECT().
+			DeleteQuote(gomock.Any(), &application.DeleteQuoteRequest{ID: 404}).
+			Return(&application.DeleteQuoteResponse{Success: false}, fmt.Errorf("failed to delete quote: %w", application.ErrNotFound))
+
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/404", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
 	t.Run("Delete not success", func(t *testing.T) {
 		mockSvc.EXPECT().
 			DeleteQuote(gomock.Any(), &application.DeleteQuoteRequest{ID: 123}).
@@ -193,7 +348,7 @@ func TestAddQuote(t *testing.T) {
 		reqBody := `{"author":"Author","quote":"Quote"}`
 		mockSvc.EXPECT().
 			AddQuote(gomock.Any(), &application.AddQuoteRequest{Author: "Author", Quote: "Quote"}).
-			Return(&application.AddQuoteResponse{ID: 42}, nil)
+			Return(&application.AddQuoteResponse{ID: 42, Created: true, Version: 1}, nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(reqBody))
 		req.Header.Set("Content-Type", "application/json")
@@ -202,10 +357,8 @@ func TestAddQuote(t *testing.T) {
 		api.AddQuote(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code)
-		var resp map[string]int64
-		err := json.NewDecoder(rr.Body).Decode(&resp)
-		require.NoError(t, err)
-		assert.Equal(t, int64(42), resp["id"])
+		assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+		assert.JSONEq(t, `{"id":42,"created":true}`, rr.Body.String())
 	})
 
 	t.Run("Invalid JSON", func(t *testing.T) {
@@ -229,6 +382,67 @@ func TestAddQuote(t *testing.T) {
 		assert.Contains(t, rr.Body.String(), "author and quote fields are required")
 	})
 
+	t.Run("Conflict", func(t *testing.T) {
+		reqBody := `{"author":"Author","quote":"Quote"}`
+		mockSvc.EXPECT().
+			AddQuote(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("failed to add quote: %w", application.ErrConflict))
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(reqBody))
+		rr := httptest.NewRecorder()
+
+		api.AddQuote(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		reqBody := `{"author":"Author","quote":"Quote"}`
+		mockSvc.EXPECT().
+			AddQuote(gomock.Any(), gomock.Any()).
+			Return(nil, &application.DuplicateQuoteError{ExistingID: 7})
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(reqBody))
+		rr := httptest.NewRecorder()
+
+		api.AddQuote(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		var body struct {
+			Code       string `json:"code"`
+			ExistingID int64  `json:"existing_id"`
+		}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		assert.Equal(t, "conflict", body.Code)
+		assert.Equal(t, int64(7), body.ExistingID)
+	})
+
+	t.Run("Upsert returns existing quote", func(t *testing.T) {
+		reqBody := `{"author":"Author","quote":"Quote"}`
+		mockSvc.EXPECT().
+			AddQuote(gomock.Any(), &application.AddQuoteRequest{Author: "Author", Quote: "Quote", Upsert: true}).
+			Return(&application.AddQuoteResponse{ID: 7, Version: 3}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes?upsert=true", strings.NewReader(reqBody))
+		rr := httptest.NewRecorder()
+
+		api.AddQuote(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+		assert.JSONEq(t, `{"id":7,"created":false}`, rr.Body.String())
+	})
+
+	t.Run("Invalid upsert", func(t *testing.T) {
+		reqBody := `{"author":"Author","quote":"Quote"}`
+		req := httptest.NewRequest(http.MethodPost, "/quotes?upsert=maybe", strings.NewReader(reqBody))
+		rr := httptest.NewRecorder()
+
+		api.AddQuote(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
 	t.Run("App error", func(t *testing.T) {
 		reqBody := `{"author":"Author","quote":"Quote"}`
 		mockSvc.EXPECT().
@@ -285,6 +499,71 @@ func TestGetQuotes(t *testing.T) {
 		assert.Contains(t, rr.Body.String(), `"Author": "Author"`)
 	})
 
+	t.Run("Pagination params", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuotes(gomock.Any(), &application.GetQuotesRequest{Limit: 2, Cursor: "abc"}).
+			Return(&application.GetQuotesResponse{
+				Quotes:     []application.Quote{{ID: 3, Author: "C", Quote: "Q3"}},
+				NextCursor: "next",
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes?limit=2&cursor=abc", nil)
+		rr := httptest.NewRecorder()
+
+		api.GetQuotes(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"next_cursor": "next"`)
+	})
+
+	t.Run("Tag filters combined with author", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuotes(gomock.Any(), &application.GetQuotesRequest{
+				Author:       "Seneca",
+				Tags:         []string{"stoicism", "time"},
+				MatchAllTags: true,
+			}).
+			Return(&application.GetQuotesResponse{Quotes: []application.Quote{}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes?author=Seneca&tag=stoicism&tag=time&tag_mode=all", nil)
+		rr := httptest.NewRecorder()
+
+		api.GetQuotes(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Invalid tag_mode", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quotes?tag=a&tag_mode=some", nil)
+		rr := httptest.NewRecorder()
+
+		api.GetQuotes(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quotes?limit=-1", nil)
+		rr := httptest.NewRecorder()
+
+		api.GetQuotes(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Invalid cursor", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuotes(gomock.Any(), gomock.Any()).
+			Return(nil, application.ErrInvalidCursor)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes?cursor=bad", nil)
+		rr := httptest.NewRecorder()
+
+		api.GetQuotes(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
 	t.Run("App error", func(t *testing.T) {
 		mockSvc.EXPECT().
 			GetQuotes(gomock.Any(), gomock.Any()).
@@ -299,3 +578,1145 @@ func TestGetQuotes(t *testing.T) {
 		assert.Contains(t, rr.Body.String(), "Failed to get quotes")
 	})
 }
+
+func TestHandleSearchQuotes(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /quotes/search success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			SearchQuotes(gomock.Any(), &application.SearchQuotesRequest{Query: "imagination", Limit: 5}).
+			Return(&application.SearchQuotesResponse{
+				Results: []application.SearchResult{
+					{Quote: application.Quote{ID: 2, Author: "Albert Einstein"}, Rank: 0.6, Headline: "<b>Imagination</b>"},
+				},
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/search?q=imagination&limit=5", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleSearchQuotes(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"Author": "Albert Einstein"`)
+	})
+
+	t.Run("Missing q", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quotes/search", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleSearchQuotes(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Non-GET method", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/search?q=x", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleSearchQuotes(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+
+	t.Run("App error", func(t *testing.T) {
+		mockSvc.EXPECT().
+			SearchQuotes(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("fail"))
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/search?q=x", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleSearchQuotes(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Failed to search quotes")
+	})
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+	api.Config = &rest.Config{}
+	require.NoError(t, api.Init())
+
+	type envelope struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+		Details   []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"details"`
+	}
+
+	t.Run("Internal error does not leak details", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetRandomQuote(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New(`pq: relation "quotes" does not exist`))
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/random", nil)
+		req.Header.Set("X-Request-ID", "req-42")
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "req-42", rr.Header().Get("X-Request-ID"))
+		assert.NotContains(t, rr.Body.String(), "relation")
+
+		var body envelope
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		assert.Equal(t, "internal_error", body.Code)
+		assert.Equal(t, "Failed to get random quote", body.Message)
+		assert.Equal(t, "req-42", body.RequestID)
+	})
+
+	t.Run("Validation error has field details", func(t *testing.T) {
+		mockSvc.EXPECT().
+			Authenticate(gomock.Any(), &application.AuthenticateRequest{Token: "valid"}).
+			Return(&application.AuthenticateResponse{Principal: application.Principal{Subject: "bot"}}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(`{"author":"Author"}`))
+		req.Header.Set("Authorization", "Bearer valid")
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		var body envelope
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		assert.Equal(t, "validation_failed", body.Code)
+		assert.NotEmpty(t, body.RequestID)
+		require.Len(t, body.Details, 1)
+		assert.Equal(t, "quote", body.Details[0].Field)
+	})
+
+	t.Run("Application validation error", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuotes(gomock.Any(), gomock.Any()).
+			Return(nil, application.ErrInvalidCursor)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes?cursor=bad", nil)
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		var body envelope
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		assert.Equal(t, "invalid cursor", body.Message)
+		require.Len(t, body.Details, 1)
+		assert.Equal(t, "cursor", body.Details[0].Field)
+	})
+}
+
+func TestHandleTags(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /tags success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetTags(gomock.Any(), gomock.Any()).
+			Return(&application.GetTagsResponse{Tags: []application.Tag{{Name: "humor", Count: 3}}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleTags(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"name": "humor"`)
+		assert.Contains(t, rr.Body.String(), `"count": 3`)
+	})
+
+	t.Run("Non-GET method", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tags", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleTags(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestHandleAuthors(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /authors success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetAuthors(gomock.Any(), &application.GetAuthorsRequest{Limit: 10, Offset: 20}).
+			Return(&application.GetAuthorsResponse{Authors: []application.Author{
+				{ID: 1, Name: "Confucius", Aliases: []string{"confucius"}},
+			}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/authors?limit=10&offset=20", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAuthors(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"name": "Confucius"`)
+	})
+
+	t.Run("Invalid offset", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/authors?offset=-1", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAuthors(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestHandleAuthorByID(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /authors/{id} success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetAuthor(gomock.Any(), &application.GetAuthorRequest{ID: 1}).
+			Return(&application.GetAuthorResponse{Author: application.Author{ID: 1, Name: "Confucius"}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/authors/1", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAuthorByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"id": 1`)
+	})
+
+	t.Run("GET /authors/{id} not found", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetAuthor(gomock.Any(), &application.GetAuthorRequest{ID: 2}).
+			Return(nil, fmt.Errorf("failed to get author: %w", application.ErrNotFound))
+
+		req := httptest.NewRequest(http.MethodGet, "/authors/2", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAuthorByID(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("GET /authors/{id}/quotes success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetAuthorQuotes(gomock.Any(), &application.GetAuthorQuotesRequest{ID: 1, Limit: 1, Cursor: "abc"}).
+			Return(&application.GetAuthorQuotesResponse{
+				Quotes:     []application.Quote{{ID: 5, AuthorID: 1, Author: "Confucius", Quote: "Quote"}},
+				NextCursor: "next",
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/authors/1/quotes?limit=1&cursor=abc", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAuthorByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"next_cursor": "next"`)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/authors/abc", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAuthorByID(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Unknown sub-resource", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/authors/1/books", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAuthorByID(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Non-GET method", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/authors/1", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAuthorByID(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestHandleDailyQuote(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /quotes/daily success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetDailyQuote(gomock.Any(), &application.GetDailyQuoteRequest{Date: "2025-06-03"}).
+			Return(&application.GetDailyQuoteResponse{
+				Date:  "2025-06-03",
+				Quote: application.Quote{ID: 3, Author: "Mark Twain", Quote: "Q"},
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/daily?date=2025-06-03", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleDailyQuote(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var body struct {
+			Date  string            `json:"date"`
+			Quote application.Quote `json:"quote"`
+		}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		assert.Equal(t, "2025-06-03", body.Date)
+		assert.Equal(t, int64(3), body.Quote.ID)
+	})
+
+	t.Run("No quote for the day", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetDailyQuote(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("failed to get daily quote: %w", application.ErrNotFound))
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/daily?date=2020-01-01", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleDailyQuote(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Non-GET method", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/daily", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleDailyQuote(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestAuth(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+	api.Config = &rest.Config{}
+	require.NoError(t, api.Init())
+
+	t.Run("Write without token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+		assert.Contains(t, rr.Body.String(), `"code":"unauthorized"`)
+	})
+
+	t.Run("Malformed header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		mockSvc.EXPECT().
+			Authenticate(gomock.Any(), &application.AuthenticateRequest{Token: "forged"}).
+			Return(nil, application.ErrUnauthorized)
+
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		req.Header.Set("Authorization", "Bearer forged")
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Contains(t, rr.Header().Get("WWW-Authenticate"), "invalid_token")
+	})
+
+	t.Run("Valid token passes principal", func(t *testing.T) {
+		mockSvc.EXPECT().
+			Authenticate(gomock.Any(), &application.AuthenticateRequest{Token: "valid"}).
+			Return(&application.AuthenticateResponse{Principal: application.Principal{Subject: "bot"}}, nil)
+		mockSvc.EXPECT().
+			DeleteQuote(gomock.Any(), &application.DeleteQuoteRequest{ID: 1}).
+			DoAndReturn(func(ctx context.Context, _ *application.DeleteQuoteRequest) (*application.DeleteQuoteResponse, error) {
+				p, ok := application.PrincipalFromContext(ctx)
+				require.True(t, ok)
+				assert.Equal(t, "bot", p.Subject)
+				return &application.DeleteQuoteResponse{Success: true}, nil
+			})
+
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		req.Header.Set("Authorization", "Bearer valid")
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("Role not allowed", func(t *testing.T) {
+		mockSvc.EXPECT().
+			Authenticate(gomock.Any(), gomock.Any()).
+			Return(&application.AuthenticateResponse{
+				Principal: application.Principal{Subject: "bot", Role: application.RoleReader},
+			}, nil)
+		mockSvc.EXPECT().
+			DeleteQuote(gomock.Any(), gomock.Any()).
+			Return(nil, application.ErrForbidden)
+
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		req.Header.Set("Authorization", "Bearer reader-token")
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"code":"forbidden"`)
+	})
+
+	t.Run("Read without token", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetTags(gomock.Any(), gomock.Any()).
+			Return(&application.GetTagsResponse{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+		rr := httptest.NewRecorder()
+
+		api.Server.Handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+}
+
+func TestHandleTrash(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /quotes/trash success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetTrash(gomock.Any(), &application.GetTrashRequest{Limit: 5, Offset: 10}).
+			Return(&application.GetTrashResponse{Quotes: []application.Quote{{ID: 1, Quote: "Quote"}}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/trash?limit=5&offset=10", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleTrash(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"quotes"`)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetTrash(gomock.Any(), gomock.Any()).
+			Return(nil, application.ErrForbidden)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/trash", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleTrash(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("POST /quotes/{id}/restore success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			RestoreQuote(gomock.Any(), &application.RestoreQuoteRequest{ID: 1}).
+			Return(&application.RestoreQuoteResponse{Quote: application.Quote{ID: 1, Quote: "Quote"}}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/restore", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"ID": 1`)
+	})
+
+	t.Run("Restore passes If-Match", func(t *testing.T) {
+		mockSvc.EXPECT().
+			RestoreQuote(gomock.Any(), &application.RestoreQuoteRequest{ID: 1, IfVersions: []int{2}}).
+			Return(&application.RestoreQuoteResponse{Quote: application.Quote{ID: 1, Quote: "Quote", Version: 3}}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/restore", nil)
+		req.Header.Set("If-Match", `"2"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	})
+
+	t.Run("Restore without If-Match when required", func(t *testing.T) {
+		strict := &rest.Service{App: mockSvc, Log: api.Log, Config: &rest.Config{RequireIfMatch: true}}
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/restore", nil)
+		rr := httptest.NewRecorder()
+
+		strict.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, rr.Code)
+	})
+
+	t.Run("Restore conflicts with a re-added quote", func(t *testing.T) {
+		mockSvc.EXPECT().
+			RestoreQuote(gomock.Any(), &application.RestoreQuoteRequest{ID: 2}).
+			Return(nil, fmt.Errorf("failed to restore quote: %w", application.ErrConflict))
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/2/restore", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("Restore with GET", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quotes/1/restore", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestHandleAudit(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /audit with filters", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetAuditEvents(gomock.Any(), &application.GetAuditEventsRequest{
+				QuoteID: 7,
+				Actor:   "alice",
+				Since:   "2025-06-01",
+				Limit:   10,
+			}).
+			Return(&application.GetAuditEventsResponse{Events: []application.AuditEvent{
+				{ID: 1, QuoteID: 7, Actor: "alice", Action: "delete", RequestID: "req-1"},
+			}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/audit?quote_id=7&actor=alice&since=2025-06-01&limit=10", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAudit(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"action": "delete"`)
+		assert.Contains(t, rr.Body.String(), `"request_id": "req-1"`)
+	})
+
+	t.Run("Invalid quote_id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/audit?quote_id=abc", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAudit(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetAuditEvents(gomock.Any(), gomock.Any()).
+			Return(nil, application.ErrForbidden)
+
+		req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleAudit(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
+
+func TestHandleQuoteRevisions(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /quotes/{id}/revisions success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuoteRevisions(gomock.Any(), &application.GetQuoteRevisionsRequest{ID: 1}).
+			Return(&application.GetQuoteRevisionsResponse{Revisions: []application.QuoteRevision{
+				{Revision: 2, Author: "Author", Quote: "Edited"},
+				{Revision: 1, Author: "Author", Quote: "Original"},
+			}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/1/revisions", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"revision": 2`)
+	})
+
+	t.Run("POST /quotes/{id}/revisions/{rev}/restore success", func(t *testing.T) {
+		mockSvc.EXPECT().
+			RestoreQuoteRevision(gomock.Any(), &application.RestoreQuoteRevisionRequest{ID: 1, Revision: 1}).
+			Return(&application.RestoreQuoteRevisionResponse{Quote: application.Quote{ID: 1, Quote: "Original"}}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/revisions/1/restore", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"Quote": "Original"`)
+	})
+
+	t.Run("Revision restore with stale If-Match", func(t *testing.T) {
+		mockSvc.EXPECT().
+			RestoreQuoteRevision(gomock.Any(), &application.RestoreQuoteRevisionRequest{ID: 1, Revision: 1, IfVersions: []int{2}}).
+			Return(nil, fmt.Errorf("failed to restore quote revision: %w", application.ErrPreconditionFailed))
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/revisions/1/restore", nil)
+		req.Header.Set("If-Match", `"2"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	})
+
+	t.Run("Revision restore without If-Match when required", func(t *testing.T) {
+		strict := &rest.Service{App: mockSvc, Log: api.Log, Config: &rest.Config{RequireIfMatch: true}}
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/revisions/1/restore", nil)
+		rr := httptest.NewRecorder()
+
+		strict.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, rr.Code)
+	})
+
+	t.Run("Unknown revision", func(t *testing.T) {
+		mockSvc.EXPECT().
+			RestoreQuoteRevision(gomock.Any(), &application.RestoreQuoteRevisionRequest{ID: 1, Revision: 9}).
+			Return(nil, fmt.Errorf("failed to restore quote revision: %w", application.ErrNotFound))
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/revisions/9/restore", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("Invalid revision", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/revisions/abc/restore", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("GET /quotes/{id}/revisions/{rev}/diff", func(t *testing.T) {
+		mockSvc.EXPECT().
+			DiffQuoteRevisions(gomock.Any(), &application.DiffQuoteRevisionsRequest{ID: 1, From: 1, To: 3}).
+			Return(&application.DiffQuoteRevisionsResponse{Diff: application.RevisionDiff{
+				From: 1,
+				To:   3,
+				Changes: []application.FieldChange{{Field: "quote", From: "Original", To: "Edited", Edits: []application.TextEdit{
+					{Op: application.EditDelete, Text: "Original"},
+					{Op: application.EditInsert, Text: "Edited"},
+				}}},
+			}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/1/revisions/3/diff?against=1", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"from":1,"to":3,"changes":[{"field":"quote","from":"Original","to":"Edited",
+			"edits":[{"op":"delete","text":"Original"},{"op":"insert","text":"Edited"}]}]}`, rr.Body.String())
+	})
+
+	t.Run("Diff with invalid against", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quotes/1/revisions/3/diff?against=0", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Diff with POST", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/revisions/3/diff", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+
+	t.Run("Revisions with POST", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/1/revisions", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestConditionalRequests(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+	quote := application.Quote{ID: 1, Author: "Author", Quote: "Quote", Version: 3}
+
+	t.Run("GET sets ETag", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuote(gomock.Any(), &application.GetQuoteRequest{ID: 1}).
+			Return(&application.GetQuoteResponse{Quote: quote}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/1", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	})
+
+	t.Run("GET with matching If-None-Match", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuote(gomock.Any(), &application.GetQuoteRequest{ID: 1}).
+			Return(&application.GetQuoteResponse{Quote: quote}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/1", nil)
+		req.Header.Set("If-None-Match", `"2", W/"3"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+		assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	})
+
+	t.Run("GET with stale If-None-Match", func(t *testing.T) {
+		mockSvc.EXPECT().
+			GetQuote(gomock.Any(), &application.GetQuoteRequest{ID: 1}).
+			Return(&application.GetQuoteResponse{Quote: quote}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/1", nil)
+		req.Header.Set("If-None-Match", `"2"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("PATCH passes If-Match and returns new ETag", func(t *testing.T) {
+		text := "Edited"
+		updated := quote
+		updated.Quote, updated.Version = text, 4
+		mockSvc.EXPECT().
+			UpdateQuote(gomock.Any(), &application.UpdateQuoteRequest{ID: 1, Quote: &text, IfVersions: []int{3}}).
+			Return(&application.UpdateQuoteResponse{Quote: updated}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/quotes/1", strings.NewReader(`{"quote":"Edited"}`))
+		req.Header.Set("If-Match", `"3"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+	})
+
+	t.Run("PATCH with stale If-Match", func(t *testing.T) {
+		mockSvc.EXPECT().
+			UpdateQuote(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("failed to update quote: %w", application.ErrPreconditionFailed))
+
+		req := httptest.NewRequest(http.MethodPatch, "/quotes/1", strings.NewReader(`{"quote":"Edited"}`))
+		req.Header.Set("If-Match", `"2"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"code":"precondition_failed"`)
+	})
+
+	t.Run("DELETE with malformed If-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		req.Header.Set("If-Match", `W/"3"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	})
+
+	t.Run("DELETE with If-Match list", func(t *testing.T) {
+		mockSvc.EXPECT().
+			DeleteQuote(gomock.Any(), &application.DeleteQuoteRequest{ID: 1, IfVersions: []int{3}}).
+			Return(&application.DeleteQuoteResponse{Success: true}, nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		req.Header.Set("If-Match", `W/"2", "3", "abc"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("DELETE with If-Match naming several versions", func(t *testing.T) {
+		mockSvc.EXPECT().
+			DeleteQuote(gomock.Any(), &application.DeleteQuoteRequest{ID: 1, IfVersions: []int{3, 4}}).
+			Return(&application.DeleteQuoteResponse{Success: true}, nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		req.Header.Set("If-Match", `"3", "4", "3"`)
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("DELETE with If-Match *", func(t *testing.T) {
+		mockSvc.EXPECT().
+			DeleteQuote(gomock.Any(), &application.DeleteQuoteRequest{ID: 1}).
+			Return(&application.DeleteQuoteResponse{Success: true}, nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		req.Header.Set("If-Match", "*")
+		rr := httptest.NewRecorder()
+
+		api.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("DELETE without If-Match when required", func(t *testing.T) {
+		strict := &rest.Service{App: mockSvc, Log: api.Log, Config: &rest.Config{RequireIfMatch: true}}
+
+		req := httptest.NewRequest(http.MethodDelete, "/quotes/1", nil)
+		rr := httptest.NewRecorder()
+
+		strict.HandleQuoteByID(rr, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, rr.Code)
+	})
+}
+
+func TestHandleImport(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("POST /quotes/import csv dry run", func(t *testing.T) {
+		body := "author,quote\nA,One\n"
+		mockSvc.EXPECT().
+			ImportQuotes(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *application.ImportQuotesRequest) (*application.ImportQuotesResponse, error) {
+				assert.Equal(t, application.ImportCSV, req.Format)
+				assert.True(t, req.DryRun)
+				data, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, body, string(data))
+				return &application.ImportQuotesResponse{
+					Inserted: 1,
+					DryRun:   true,
+					Rows:     []application.ImportRow{{Row: 1, Status: application.ImportInserted}},
+				}, nil
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/import?dry_run=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+		rr := httptest.NewRecorder()
+
+		api.HandleImport(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"inserted": 1`)
+		assert.Contains(t, rr.Body.String(), `"status": "inserted"`)
+	})
+
+	t.Run("NDJSON content type", func(t *testing.T) {
+		mockSvc.EXPECT().
+			ImportQuotes(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *application.ImportQuotesRequest) (*application.ImportQuotesResponse, error) {
+				assert.Equal(t, application.ImportNDJSON, req.Format)
+				assert.False(t, req.DryRun)
+				return &application.ImportQuotesResponse{}, nil
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/import", strings.NewReader(`{"author":"A","quote":"One"}`))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		rr := httptest.NewRecorder()
+
+		api.HandleImport(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("Unsupported content type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/import", strings.NewReader("<quotes/>"))
+		req.Header.Set("Content-Type", "application/xml")
+		rr := httptest.NewRecorder()
+
+		api.HandleImport(rr, req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"code":"unsupported_media_type"`)
+	})
+
+	t.Run("Invalid dry_run", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/import?dry_run=maybe", strings.NewReader("[]"))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		api.HandleImport(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Malformed body", func(t *testing.T) {
+		mockSvc.EXPECT().
+			ImportQuotes(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("failed to import quotes: %w", application.ErrValidation))
+
+		req := httptest.NewRequest(http.MethodPost, "/quotes/import", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+
+		api.HandleImport(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("GET /quotes/import", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quotes/import", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleImport(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+func TestHandleExport(t *testing.T) {
+	api, mockSvc := newTestAPI(t)
+
+	t.Run("GET /quotes/export csv", func(t *testing.T) {
+		mockSvc.EXPECT().
+			ExportQuotes(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *application.ExportQuotesRequest) error {
+				assert.Equal(t, application.ExportCSV, req.Format)
+				assert.Equal(t, "Seneca", req.Author)
+				assert.Equal(t, []string{"stoic", "luck"}, req.Tags)
+				assert.True(t, req.MatchAllTags)
+				_, err := io.WriteString(req.Out, "id,author,quote\n")
+				return err
+			})
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/export?format=csv&author=Seneca&tag=stoic&tag=luck&tag_mode=all", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleExport(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Regexp(t, `^attachment; filename="quotes-\d{4}-\d{2}-\d{2}\.csv"$`, rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, "id,author,quote\n", rr.Body.String())
+	})
+
+	t.Run("JSON by default", func(t *testing.T) {
+		mockSvc.EXPECT().
+			ExportQuotes(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *application.ExportQuotesRequest) error {
+				assert.Equal(t, application.ExportJSON, req.Format)
+				_, err := io.WriteString(req.Out, "[]")
+				return err
+			})
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/export", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleExport(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Header().Get("Content-Disposition"), `.json"`)
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/quotes/export?format=xml", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleExport(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"field":"format"`)
+	})
+
+	t.Run("Error before streaming", func(t *testing.T) {
+		mockSvc.EXPECT().
+			ExportQuotes(gomock.Any(), gomock.Any()).
+			Return(errors.New("connection refused"))
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/export?format=markdown", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleExport(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("JSON storage error before the first quote", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStorage := storagemocks.NewMockQuoteStorage(ctrl)
+		mockStorage.EXPECT().
+			ExportQuotes(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(errors.New("failed to acquire connection"))
+		api := &rest.Service{
+			App: &application.Service{DB: mockStorage, Log: slog.New(slog.NewTextHandler(io.Discard, nil))},
+			Log: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/export?format=json", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleExport(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Disposition"))
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, "internal_error", body["code"])
+	})
+
+	t.Run("Error while streaming", func(t *testing.T) {
+		mockSvc.EXPECT().
+			ExportQuotes(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *application.ExportQuotesRequest) error {
+				_, _ = io.WriteString(req.Out, "{}\n")
+				return errors.New("connection reset")
+			})
+
+		req := httptest.NewRequest(http.MethodGet, "/quotes/export?format=ndjson", nil)
+		rr := httptest.NewRecorder()
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() { api.HandleExport(rr, req) })
+	})
+
+	t.Run("POST /quotes/export", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/quotes/export", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleExport(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
+
+// fakeComponent is a service with a fixed health.
+type fakeComponent struct {
+	health service.Health
+}
+
+func (c fakeComponent) Init() error                                { return nil }
+func (c fakeComponent) Run(context.Context)                        {}
+func (c fakeComponent) Stop()                                      {}
+func (c fakeComponent) CheckHealth(context.Context) service.Health { return c.health }
+
+func checkHealth(t *testing.T, handler http.HandlerFunc, path string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	return rr.Code, body
+}
+
+func TestHandleHealth(t *testing.T) {
+	t.Run("Listener lifecycle", func(t *testing.T) {
+		api, _ := newTestAPI(t)
+		api.Config = &rest.Config{}
+		require.NoError(t, api.Init())
+
+		code, body := checkHealth(t, api.HandleReadiness, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "down", body["status"])
+		code, _ = checkHealth(t, api.HandleLiveness, "/healthz")
+		assert.Equal(t, http.StatusOK, code)
+
+		done := make(chan struct{})
+		go func() {
+			api.Run(context.Background())
+			close(done)
+		}()
+		assert.Eventually(t, func() bool {
+			code, _ := checkHealth(t, api.HandleReadiness, "/readyz")
+			return code == http.StatusOK
+		}, 5*time.Second, 10*time.Millisecond)
+
+		api.Stop()
+		<-done
+
+		code, body = checkHealth(t, api.HandleReadiness, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, true, body["shutting_down"])
+		components := body["components"].(map[string]any)
+		assert.Equal(t, map[string]any{"status": "down", "error": "shutting down", "details": map[string]any{"addr": ":0"}},
+			components["http"])
+		code, _ = checkHealth(t, api.HandleLiveness, "/healthz")
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("Per-component detail", func(t *testing.T) {
+		api, _ := newTestAPI(t)
+		mgr := service.NewManager(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		mgr.AddService(
+			fakeComponent{health: service.Health{Name: "postgres", Live: true, Error: "connection refused"}},
+			fakeComponent{health: service.Health{Name: "http", Live: true, Ready: true}},
+		)
+		api.Health = mgr
+
+		code, body := checkHealth(t, api.HandleReadiness, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, map[string]any{
+			"status": "down",
+			"components": map[string]any{
+				"postgres": map[string]any{"status": "down", "error": "connection refused"},
+				"http":     map[string]any{"status": "up"},
+			},
+		}, body)
+
+		code, body = checkHealth(t, api.HandleLiveness, "/healthz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "up", body["status"])
+		assert.Equal(t, "up", body["components"].(map[string]any)["postgres"].(map[string]any)["status"])
+	})
+
+	t.Run("Dead component", func(t *testing.T) {
+		api, _ := newTestAPI(t)
+		mgr := service.NewManager(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		mgr.AddService(fakeComponent{health: service.Health{Name: "http", Error: "listener failed"}})
+		api.Health = mgr
+
+		code, body := checkHealth(t, api.HandleLiveness, "/healthz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "down", body["status"])
+	})
+
+	t.Run("POST /readyz", func(t *testing.T) {
+		api, _ := newTestAPI(t)
+		req := httptest.NewRequest(http.MethodPost, "/readyz", nil)
+		rr := httptest.NewRecorder()
+
+		api.HandleReadiness(rr, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Audit actions recorded by the quote mutations.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditRevert  = "revert"
+	AuditPurge   = "purge"
+)
+
+// Actor identifies who makes a change. Mutations record the actor found in
+// their context with every audit event; without one the actor is left empty.
+type Actor struct {
+	Subject   string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx whose mutations are attributed to a.
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// ActorFromContext returns the actor stored by WithActor, or the zero Actor.
+func ActorFromContext(ctx context.Context) Actor {
+	a, _ := ctx.Value(actorKey{}).(Actor)
+	return a
+}
+
+// AuditEvent is a recorded mutation. Before and After hold the quote as JSON;
+// Before is empty for creations and After for purges.
+type AuditEvent struct {
+	ID        int64
+	QuoteID   int64
+	Actor     string
+	Action    string
+	Before    json.RawMessage
+	After     json.RawMessage
+	RequestID string
+	CreatedAt time.Time
+}
+
+// AuditFilter narrows GetAuditEvents. Zero fields do not filter.
+type AuditFilter struct {
+	QuoteID int64
+	Actor   string
+	Since   time.Time
+}
+
+// recordAudit stores an audit event for a mutation of quote id inside tx.
+func recordAudit(ctx context.Context, tx pgx.Tx, action string, id int64, before, after *Quote) error {
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return err
+	}
+
+	actor := ActorFromContext(ctx)
+	_, err = tx.Exec(ctx,
+		`INSERT INTO audit_events (quote_id, actor, action, before, after, request_id)
+		 VALUES ($1, NULLIF($2, ''), $3, $4, $5, NULLIF($6, ''))`,
+		id, actor.Subject, action, beforeJSON, afterJSON, actor.RequestID)
+	return err
+}
+
+func auditJSON(q *Quote) (any, error) {
+	if q == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit snapshot: %w", err)
+	}
+	return string(b), nil
+}
+
+// getQuoteTx reads a quote inside tx regardless of whether it is trashed.
+// With lock set the row stays locked until tx ends.
+func getQuoteTx(ctx context.Context, tx pgx.Tx, id int64, lock bool) (*Quote, error) {
+	query := `SELECT ` + quoteColumns + ` FROM quotes WHERE id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	q, err := scanQuote(tx.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return q, nil
+}
+
+// GetAuditEvents lists audit events, newest first.
+func (db *DB) GetAuditEvents(ctx context.Context, filter AuditFilter, limit, offset int) ([]*AuditEvent, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	var (
+		args  queryArgs
+		conds []string
+	)
+	if filter.QuoteID != 0 {
+		conds = append(conds, "quote_id = "+args.add(filter.QuoteID))
+	}
+	if filter.Actor != "" {
+		conds = append(conds, "actor = "+args.add(filter.Actor))
+	}
+	if !filter.Since.IsZero() {
+		conds = append(conds, "created_at >= "+args.add(filter.Since))
+	}
+
+	query := `SELECT id, quote_id, COALESCE(actor, ''), action, before, after, COALESCE(request_id, ''), created_at
+		FROM audit_events` + where(conds) +
+		` ORDER BY id DESC LIMIT ` + args.add(limit) + ` OFFSET ` + args.add(offset)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*AuditEvent
+	for rows.Next() {
+		var e AuditEvent
+		if err := rows.Scan(&e.ID, &e.QuoteID, &e.Actor, &e.Action, &e.Before, &e.After, &e.RequestID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
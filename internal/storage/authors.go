@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const authorColumns = `authors.id, authors.name,
+	ARRAY(SELECT al.alias FROM author_aliases al
+	      WHERE al.author_id = authors.id ORDER BY al.alias) AS aliases,
+	COALESCE(authors.bio, ''), authors.birth_year, authors.death_year`
+
+func scanAuthor(row pgx.Row) (*Author, error) {
+	var a Author
+	if err := row.Scan(&a.ID, &a.Name, &a.Aliases, &a.Bio, &a.BirthYear, &a.DeathYear); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// resolveAuthor maps a spelling onto its canonical author inside tx, creating
+// the author when the spelling is unknown. It returns the author id and name.
+func resolveAuthor(ctx context.Context, tx pgx.Tx, spelling string) (int64, string, error) {
+	var (
+		id   int64
+		name string
+	)
+	err := tx.QueryRow(ctx,
+		`SELECT a.id, a.name
+		 FROM author_aliases al JOIN authors a ON a.id = al.author_id
+		 WHERE al.alias = `+aliasKey("$1"), spelling).Scan(&id, &name)
+	if err == nil {
+		return id, name, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", err
+	}
+
+	// The no-op update makes RETURNING yield the row a concurrent insert won with.
+	err = tx.QueryRow(ctx,
+		`INSERT INTO authors (name) VALUES (btrim($1))
+		 ON CONFLICT ((lower(name))) DO UPDATE SET name = authors.name
+		 RETURNING id, name`, spelling).Scan(&id, &name)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO author_aliases (alias, author_id) VALUES (`+aliasKey("$1")+`, $2)
+		 ON CONFLICT (alias) DO NOTHING`, spelling, id); err != nil {
+		return 0, "", err
+	}
+	return id, name, nil
+}
+
+func (db *DB) GetAuthors(ctx context.Context, limit, offset int) ([]*Author, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx,
+		`SELECT `+authorColumns+` FROM authors
+		 ORDER BY lower(authors.name), authors.id
+		 LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var authors []*Author
+	for rows.Next() {
+		a, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, a)
+	}
+	return authors, rows.Err()
+}
+
+func (db *DB) GetAuthorByID(ctx context.Context, id int64) (*Author, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	a, err := scanAuthor(conn.QueryRow(ctx,
+		`SELECT `+authorColumns+` FROM authors WHERE authors.id = $1`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return a, nil
+}
@@ -20,6 +20,10 @@ type Config struct {
 	MaxOpenConns     int32         `env:"MAX_OPEN_CONNS" envDefault:"10" yaml:"max-open-conns"`
 	ConnIdleLifetime time.Duration `env:"CONN_IDLE_LIFETIME" envDefault:"10m" yaml:"conn-idle-lifetime"`
 	ConnMaxLifetime  time.Duration `env:"CONN_MAX_LIFETIME" envDefault:"1h" yaml:"conn-max-lifetime"`
+	// AutoMigrate makes DB.Init apply pending migrations before the pool is used.
+	AutoMigrate bool `env:"AUTO_MIGRATE" envDefault:"false" yaml:"auto-migrate"`
+	// MigrateLockTimeout bounds how long DB.Init waits for another replica's migrations.
+	MigrateLockTimeout time.Duration `env:"MIGRATE_LOCK_TIMEOUT" envDefault:"5m" yaml:"migrate-lock-timeout"`
 }
 
 func (config Config) dsnPostgres(log *slog.Logger) string {
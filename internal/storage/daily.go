@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// dailyPickAttempts bounds retries when a concurrent pick for another day took the same quote.
+	dailyPickAttempts = 3
+)
+
+// GetDailyQuote returns the quote selected for the calendar day of day,
+// or ErrNotFound when nothing was selected for it.
+func (db *DB) GetDailyQuote(ctx context.Context, day time.Time) (*Quote, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	q, err := scanQuote(conn.QueryRow(ctx,
+		`SELECT `+quoteColumns+`
+		 FROM daily_quotes d JOIN quotes ON quotes.id = d.quote_id
+		 WHERE d.day = $1::date AND quotes.deleted_at IS NULL`, day.Format(dateLayout)))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return q, nil
+}
+
+// PickDailyQuote returns the quote for the calendar day of day, selecting and
+// persisting one first if the day has none or its quote was trashed. Replicas
+// racing on the same day agree on whichever selection was stored first.
+func (db *DB) PickDailyQuote(ctx context.Context, day time.Time) (*Quote, error) {
+	for i := 0; i < dailyPickAttempts; i++ {
+		q, err := db.GetDailyQuote(ctx, day)
+		if !errors.Is(err, ErrNotFound) {
+			return q, err
+		}
+		if err := db.selectDailyQuote(ctx, day); err != nil {
+			return nil, err
+		}
+	}
+	return db.GetDailyQuote(ctx, day)
+}
+
+// selectDailyQuote stores a random quote not yet shown in the current cycle for day,
+// starting a new cycle when all quotes have been shown. The random sort runs once a day.
+func (db *DB) selectDailyQuote(ctx context.Context, day time.Time) error {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			db.log.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	var cycle int
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(max(cycle), 1) FROM daily_quotes`).Scan(&cycle); err != nil {
+		return err
+	}
+
+	var quoteID int64
+	err = tx.QueryRow(ctx,
+		`SELECT id FROM quotes
+		 WHERE deleted_at IS NULL
+		   AND NOT EXISTS (SELECT 1 FROM daily_quotes d WHERE d.cycle = $1 AND d.quote_id = quotes.id)
+		 ORDER BY random() LIMIT 1`, cycle).Scan(&quoteID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		cycle++
+		err = tx.QueryRow(ctx,
+			`SELECT id FROM quotes WHERE deleted_at IS NULL ORDER BY random() LIMIT 1`).Scan(&quoteID)
+	}
+	if err != nil {
+		return translateError(err)
+	}
+
+	// A selection whose quote was trashed is replaced. Losing a race on
+	// (cycle, quote_id) to another day is left to the caller's retry.
+	_, err = tx.Exec(ctx,
+		`INSERT INTO daily_quotes (day, quote_id, cycle) VALUES ($1::date, $2, $3)
+		 ON CONFLICT (day) DO UPDATE SET quote_id = EXCLUDED.quote_id, cycle = EXCLUDED.cycle
+		 WHERE EXISTS (SELECT 1 FROM quotes
+		               WHERE quotes.id = daily_quotes.quote_id AND quotes.deleted_at IS NOT NULL)`,
+		day.Format(dateLayout), quoteID, cycle)
+	if errors.Is(translateError(err), ErrConflict) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const pgUniqueViolation = "23505"
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+	// ErrVersionMismatch is returned by conditional writes when the quote has changed since the given version.
+	ErrVersionMismatch = errors.New("version mismatch")
+	// ErrNotOwner is returned by writes limited to an owner when the quote was added by someone else.
+	ErrNotOwner = errors.New("not the owner")
+)
+
+// DuplicateError is returned by AddQuote when a quote with the same normalized
+// text already exists. It matches ErrConflict.
+type DuplicateError struct {
+	ID int64
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate of quote %d", e.ID)
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// translateError maps driver errors onto the storage sentinels, keeping the
+// original error in the chain for logging.
+func translateError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return fmt.Errorf("%w: %s", ErrConflict, pgErr.ConstraintName)
+	}
+	return err
+}
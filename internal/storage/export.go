@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// exportFetchSize is how many rows each FETCH from the export cursor reads.
+const exportFetchSize = 500
+
+// ExportQuotes calls fn for every live quote matching filter, newest first.
+// Rows are read through a server-side cursor in a read-only repeatable read
+// transaction, so the export is a consistent snapshot without holding the
+// whole result in memory. An error returned by fn stops the export.
+func (db *DB) ExportQuotes(ctx context.Context, filter QuoteFilter, fn func(*Quote) error) error {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			db.log.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	var args queryArgs
+	conds := append(filterConditions(filter, &args), liveCondition)
+	if _, err := tx.Exec(ctx,
+		`DECLARE export_quotes NO SCROLL CURSOR FOR
+		 SELECT `+quoteColumns+` FROM quotes`+where(conds)+`
+		 ORDER BY quotes.created_at DESC, quotes.id DESC`, args...); err != nil {
+		return err
+	}
+
+	fetch := `FETCH FORWARD ` + strconv.Itoa(exportFetchSize) + ` FROM export_quotes`
+	for {
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			return err
+		}
+		quotes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*Quote, error) {
+			return scanQuote(row)
+		})
+		if err != nil {
+			return err
+		}
+		for _, q := range quotes {
+			if err := fn(q); err != nil {
+				return err
+			}
+		}
+		if len(quotes) < exportFetchSize {
+			break
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -5,9 +5,27 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"log/slog"
+	"math/rand/v2"
+	"slices"
 )
 
+const quoteColumns = `quotes.id, quotes.author_id, quotes.author, quotes.quote, COALESCE(quotes.lang, ''),
+	COALESCE(quotes.created_by, ''), quotes.version, quotes.created_at, quotes.updated_at, quotes.deleted_at,
+	ARRAY(SELECT t.name FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id
+	      WHERE qt.quote_id = quotes.id ORDER BY t.name) AS tags`
+
+// scanQuote reads a row selected with quoteColumns, followed by any extra columns.
+func scanQuote(row pgx.Row, extra ...any) (*Quote, error) {
+	var q Quote
+	dest := append([]any{&q.ID, &q.AuthorID, &q.Author, &q.Quote, &q.Lang, &q.CreatedBy, &q.Version, &q.CreatedAt, &q.UpdatedAt, &q.DeletedAt, &q.Tags}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
 func (db *DB) AddQuote(ctx context.Context, quote *Quote) (int64, error) {
 	conn, err := db.pool.Acquire(ctx)
 	if err != nil {
@@ -25,13 +43,41 @@ func (db *DB) AddQuote(ctx context.Context, quote *Quote) (int64, error) {
 		}
 	}()
 
+	authorID, author, err := resolveAuthor(ctx, tx, quote.Author)
+	if err != nil {
+		return 0, err
+	}
+
 	var id int64
 	err = tx.QueryRow(ctx,
-		`INSERT INTO quotes (author, quote, created_at)
-		 VALUES ($1, $2, NOW())
+		`INSERT INTO quotes (author_id, author, quote, lang, created_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NOW(), NOW())
+		 ON CONFLICT (content_hash) WHERE deleted_at IS NULL DO NOTHING
 		 RETURNING id`,
-		quote.Author, quote.Quote).Scan(&id)
+		authorID, author, quote.Quote, quote.Lang, quote.CreatedBy).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if err := tx.QueryRow(ctx,
+			`SELECT id FROM quotes WHERE content_hash = quote_content_hash($1) AND deleted_at IS NULL`, quote.Quote).Scan(&id); err != nil {
+			return 0, err
+		}
+		return 0, &DuplicateError{ID: id}
+	}
 	if err != nil {
+		return 0, translateError(err)
+	}
+
+	if err := setQuoteTags(ctx, tx, id, quote.Tags); err != nil {
+		return 0, err
+	}
+
+	created, err := getQuoteTx(ctx, tx, id, false)
+	if err != nil {
+		return 0, err
+	}
+	if err := recordRevision(ctx, tx, created); err != nil {
+		return 0, err
+	}
+	if err := recordAudit(ctx, tx, AuditCreate, id, nil, created); err != nil {
 		return 0, err
 	}
 
@@ -41,49 +87,301 @@ func (db *DB) AddQuote(ctx context.Context, quote *Quote) (int64, error) {
 	return id, nil
 }
 
-func (db *DB) GetAllQuotes(ctx context.Context) ([]*Quote, error) {
+// setQuoteTags attaches tags to a quote inside tx, creating missing tags.
+func setQuoteTags(ctx context.Context, tx pgx.Tx, quoteID int64, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`, tags); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx,
+		`INSERT INTO quote_tags (quote_id, tag_id)
+		 SELECT $1, id FROM tags WHERE name = ANY($2)
+		 ON CONFLICT DO NOTHING`, quoteID, tags)
+	return err
+}
+
+func (db *DB) GetAllQuotes(ctx context.Context, filter QuoteFilter, page Page) ([]*Quote, *Cursor, error) {
+	return db.listQuotes(ctx, filter, page)
+}
+
+// randomQuoteAttempts bounds how many random ids are probed before falling back
+// to the next existing id, which is slightly biased towards rows after gaps.
+const randomQuoteAttempts = 5
+
+// GetRandomQuote samples an id from [min(id), max(id)] instead of sorting the
+// whole table, so every call costs a few primary-key lookups. That is only fair
+// while live ids are dense: matches of a filter are spread over the id range
+// with arbitrary gaps, so filtered requests pick a random offset among the
+// matching rows instead.
+func (db *DB) GetRandomQuote(ctx context.Context, filter QuoteFilter) (*Quote, error) {
 	conn, err := db.pool.Acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Release()
 
-	rows, err := conn.Query(ctx,
-		`SELECT id, author, quote, created_at FROM quotes ORDER BY created_at DESC`)
+	var args queryArgs
+	if conds := filterConditions(filter, &args); len(conds) > 0 {
+		return randomFilteredQuote(ctx, conn, where(append(conds, liveCondition)), args)
+	}
+
+	var minID, maxID *int64
+	if err := conn.QueryRow(ctx,
+		`SELECT min(id), max(id) FROM quotes WHERE deleted_at IS NULL`).Scan(&minID, &maxID); err != nil {
+		return nil, err
+	}
+	if minID == nil {
+		return nil, ErrNotFound
+	}
+
+	var id int64
+	for i := 0; i < randomQuoteAttempts; i++ {
+		id = *minID + rand.Int64N(*maxID-*minID+1)
+		q, err := scanQuote(conn.QueryRow(ctx,
+			`SELECT `+quoteColumns+` FROM quotes WHERE id = $1 AND deleted_at IS NULL`, id))
+		if err == nil {
+			return q, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	q, err := scanQuote(conn.QueryRow(ctx,
+		`SELECT `+quoteColumns+` FROM quotes WHERE id >= $1 AND deleted_at IS NULL ORDER BY id LIMIT 1`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return q, nil
+}
+
+// randomFilteredQuote counts the rows matching whereClause and reads one at a
+// random offset, retrying when rows were deleted in between.
+func randomFilteredQuote(ctx context.Context, conn *pgxpool.Conn, whereClause string, args queryArgs) (*Quote, error) {
+	for i := 0; i < randomQuoteAttempts; i++ {
+		var total int64
+		if err := conn.QueryRow(ctx, `SELECT count(*) FROM quotes`+whereClause, args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		if total == 0 {
+			return nil, ErrNotFound
+		}
+
+		offsetArgs := append(queryArgs{}, args...)
+		offset := offsetArgs.add(rand.Int64N(total))
+		q, err := scanQuote(conn.QueryRow(ctx,
+			`SELECT `+quoteColumns+` FROM quotes`+whereClause+` ORDER BY quotes.id LIMIT 1 OFFSET `+offset,
+			offsetArgs...))
+		if err == nil {
+			return q, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (db *DB) GetQuoteByID(ctx context.Context, id int64) (*Quote, error) {
+	conn, err := db.pool.Acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer conn.Release()
+
+	q, err := scanQuote(conn.QueryRow(ctx,
+		`SELECT `+quoteColumns+` FROM quotes WHERE id = $1 AND deleted_at IS NULL`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return q, nil
+}
+
+func (db *DB) GetQuotesByAuthor(ctx context.Context, author string, page Page) ([]*Quote, *Cursor, error) {
+	return db.listQuotes(ctx, QuoteFilter{Author: author}, page)
+}
+
+// listQuotes returns one keyset page of filtered quotes ordered by (created_at, id) descending.
+// One row past the limit is fetched to tell whether a next page exists.
+func (db *DB) listQuotes(ctx context.Context, filter QuoteFilter, page Page) ([]*Quote, *Cursor, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Release()
+
+	var args queryArgs
+	conds := append(filterConditions(filter, &args), liveCondition)
+	if page.After != nil {
+		conds = append(conds, fmt.Sprintf("(quotes.created_at, quotes.id) < (%s, %s)",
+			args.add(page.After.CreatedAt), args.add(page.After.ID)))
+	}
+
+	query := `SELECT ` + quoteColumns + ` FROM quotes` + where(conds) +
+		` ORDER BY quotes.created_at DESC, quotes.id DESC LIMIT ` + args.add(page.Limit+1)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
 	defer rows.Close()
 
 	var quotes []*Quote
 	for rows.Next() {
-		var q Quote
-		if err := rows.Scan(&q.ID, &q.Author, &q.Quote, &q.CreatedAt); err != nil {
-			return nil, err
+		q, err := scanQuote(rows)
+		if err != nil {
+			return nil, nil, err
 		}
-		quotes = append(quotes, &q)
+		quotes = append(quotes, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(quotes) <= page.Limit {
+		return quotes, nil, nil
 	}
-	return quotes, rows.Err()
+	quotes = quotes[:page.Limit]
+	last := quotes[len(quotes)-1]
+	return quotes, &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}, nil
 }
 
-func (db *DB) GetRandomQuote(ctx context.Context) (*Quote, error) {
+func (db *DB) UpdateQuote(ctx context.Context, id int64, upd *QuoteUpdate) (*Quote, error) {
 	conn, err := db.pool.Acquire(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Release()
 
-	var q Quote
-	err = conn.QueryRow(ctx,
-		`SELECT id, author, quote, created_at FROM quotes ORDER BY RANDOM() LIMIT 1`).Scan(
-		&q.ID, &q.Author, &q.Quote, &q.CreatedAt)
+	tx, err := conn.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &q, nil
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			db.log.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	before, err := getQuoteTx(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if before.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	if err := matchVersion(before, upd.Versions); err != nil {
+		return nil, err
+	}
+
+	var authorID *int64
+	var author *string
+	if upd.Author != nil {
+		resolvedID, resolved, err := resolveAuthor(ctx, tx, *upd.Author)
+		if err != nil {
+			return nil, err
+		}
+		authorID, author = &resolvedID, &resolved
+	}
+
+	q, err := scanQuote(tx.QueryRow(ctx,
+		`UPDATE quotes
+		 SET author_id = COALESCE($2, author_id),
+		     author = COALESCE($3, author),
+		     quote = COALESCE($4, quote),
+		     version = version + 1,
+		     updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+quoteColumns,
+		id, authorID, author, upd.Quote))
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if err := recordRevision(ctx, tx, q); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, AuditUpdate, id, before, q); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// DeleteQuote moves a quote to the trash; PurgeDeletedQuotes removes it for good.
+// A non-empty owner limits the delete to quotes created by that subject,
+// failing with ErrNotOwner otherwise. Versions works like in QuoteUpdate.
+func (db *DB) DeleteQuote(ctx context.Context, id int64, owner string, versions []int) error {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			db.log.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	before, err := getQuoteTx(ctx, tx, id, true)
+	if err == nil && before.DeletedAt != nil {
+		err = ErrNotFound
+	}
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("quote with id %d: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if err := matchVersion(before, versions); err != nil {
+		return err
+	}
+
+	deleted, err := scanQuote(tx.QueryRow(ctx,
+		`UPDATE quotes SET deleted_at = NOW(), version = version + 1
+		 WHERE id = $1 AND `+ownerCondition+`
+		 RETURNING `+quoteColumns, id, owner))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotOwner
+	}
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, AuditDelete, id, before, deleted); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ownerCondition limits a write by id to quotes created by the subject in $2,
+// or to none at all when $2 is empty. The row is locked beforehand, so a write
+// it filters out was made by someone else.
+const ownerCondition = `($2::text = '' OR quotes.created_by = $2)`
+
+// matchVersion returns ErrVersionMismatch unless versions is empty or holds the current version of q.
+func matchVersion(q *Quote, versions []int) error {
+	if len(versions) > 0 && !slices.Contains(versions, q.Version) {
+		return ErrVersionMismatch
+	}
+	return nil
 }
 
-func (db *DB) GetQuotesByAuthor(ctx context.Context, author string) ([]*Quote, error) {
+// SearchQuotes matches query against the author and text of live quotes. The
+// headline is cut from both, as indexed by search_vector, so a match on the
+// author is highlighted too. Stemming uses the english configuration whatever
+// the lang of a quote.
+func (db *DB) SearchQuotes(ctx context.Context, query string, limit, offset int) ([]*SearchResult, error) {
 	conn, err := db.pool.Acquire(ctx)
 	if err != nil {
 		return nil, err
@@ -91,37 +389,58 @@ func (db *DB) GetQuotesByAuthor(ctx context.Context, author string) ([]*Quote, e
 	defer conn.Release()
 
 	rows, err := conn.Query(ctx,
-		`SELECT id, author, quote, created_at FROM quotes WHERE author = $1 ORDER BY created_at DESC`, author)
+		`SELECT `+quoteColumns+`,
+		        ts_rank(search_vector, q) AS rank,
+		        ts_headline('english', concat_ws(' — ', author, quote), q,
+		                    'MaxFragments=2, MinWords=5, MaxWords=20') AS headline
+		 FROM quotes, websearch_to_tsquery('english', $1) AS q
+		 WHERE search_vector @@ q AND deleted_at IS NULL
+		 ORDER BY rank DESC, id DESC
+		 LIMIT $2 OFFSET $3`, query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var quotes []*Quote
+	var results []*SearchResult
 	for rows.Next() {
-		var q Quote
-		if err := rows.Scan(&q.ID, &q.Author, &q.Quote, &q.CreatedAt); err != nil {
+		var r SearchResult
+		q, err := scanQuote(rows, &r.Rank, &r.Headline)
+		if err != nil {
 			return nil, err
 		}
-		quotes = append(quotes, &q)
+		r.Quote = *q
+		results = append(results, &r)
 	}
-	return quotes, rows.Err()
+	return results, rows.Err()
 }
 
-func (db *DB) DeleteQuote(ctx context.Context, id int64) error {
+func (db *DB) GetTags(ctx context.Context) ([]*Tag, error) {
 	conn, err := db.pool.Acquire(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer conn.Release()
 
-	cmdTag, err := conn.Exec(ctx,
-		`DELETE FROM quotes WHERE id = $1`, id)
+	rows, err := conn.Query(ctx,
+		`SELECT t.name, count(qt.quote_id)
+		 FROM tags t
+		 LEFT JOIN quote_tags qt ON qt.tag_id = t.id
+		     AND EXISTS (SELECT 1 FROM quotes WHERE quotes.id = qt.quote_id AND quotes.deleted_at IS NULL)
+		 GROUP BY t.name
+		 ORDER BY count(qt.quote_id) DESC, t.name`)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("quote with id %d not found", id)
+	defer rows.Close()
+
+	var tags []*Tag
+	for rows.Next() {
+		var t Tag
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &t)
 	}
-	return nil
+	return tags, rows.Err()
 }
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/azaliaz/quote-service/pkg/service"
+)
+
+// CheckHealth pings Postgres. An unreachable database makes the service unready
+// but not dead: restarting it would not bring the database back.
+func (r *DB) CheckHealth(ctx context.Context) service.Health {
+	health := service.Health{Name: "postgres", Live: true}
+	if r.pool == nil {
+		health.Error = "connection pool is not initialized"
+		return health
+	}
+
+	start := time.Now()
+	if err := r.pool.Ping(ctx); err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	stat := r.pool.Stat()
+	health.Ready = true
+	health.Details = map[string]any{
+		"ping_ms":        time.Since(start).Milliseconds(),
+		"total_conns":    stat.TotalConns(),
+		"idle_conns":     stat.IdleConns(),
+		"acquired_conns": stat.AcquiredConns(),
+		"max_conns":      stat.MaxConns(),
+	}
+	return health
+}
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ImportOutcome reports what ImportQuotes did with one of the given quotes.
+// ID is the new quote for an insert and the stored one for a duplicate; in a
+// dry run it is zero when that quote would only be inserted by this batch.
+// ContentHash identifies the quote text the way the duplicate check does.
+type ImportOutcome struct {
+	Duplicate   bool
+	ID          int64
+	ContentHash string
+}
+
+var importColumns = []string{"ord", "author_id", "author", "quote", "lang", "created_by", "tags"}
+
+// ImportQuotes adds a batch of quotes in one transaction: the batch is copied
+// into a staging table with COPY and inserted with a single statement. Quotes
+// whose text is already stored, or repeats an earlier quote of the batch, are
+// reported as duplicates instead of failing the batch. With dryRun set the
+// transaction is rolled back once the outcomes are known.
+func (db *DB) ImportQuotes(ctx context.Context, quotes []*Quote, dryRun bool) ([]ImportOutcome, error) {
+	if len(quotes) == 0 {
+		return nil, nil
+	}
+
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			db.log.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	type author struct {
+		id   int64
+		name string
+	}
+	authors := make(map[string]author)
+	rows := make([][]any, 0, len(quotes))
+	for i, q := range quotes {
+		key := strings.ToLower(strings.TrimSpace(q.Author))
+		a, ok := authors[key]
+		if !ok {
+			if a.id, a.name, err = resolveAuthor(ctx, tx, q.Author); err != nil {
+				return nil, err
+			}
+			authors[key] = a
+		}
+		rows = append(rows, []any{i, a.id, a.name, q.Quote, nullIfEmpty(q.Lang), nullIfEmpty(q.CreatedBy), q.Tags})
+	}
+
+	if _, err := tx.Exec(ctx,
+		`CREATE TEMPORARY TABLE import_quotes (
+		     ord INTEGER PRIMARY KEY,
+		     author_id INTEGER NOT NULL,
+		     author VARCHAR(255) NOT NULL,
+		     quote TEXT NOT NULL,
+		     lang VARCHAR(8),
+		     created_by VARCHAR(255),
+		     tags TEXT[]
+		 ) ON COMMIT DROP`); err != nil {
+		return nil, err
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"import_quotes"}, importColumns, pgx.CopyFromRows(rows)); err != nil {
+		return nil, err
+	}
+
+	inserted, err := insertImported(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every staged row now matches a live quote: its own insert or the one it duplicates.
+	matches, err := tx.Query(ctx,
+		`SELECT s.ord, q.content_hash, q.id
+		 FROM import_quotes s
+		 JOIN quotes q ON q.content_hash = quote_content_hash(s.quote) AND q.deleted_at IS NULL
+		 ORDER BY s.ord`)
+	if err != nil {
+		return nil, err
+	}
+	outcomes := make([]ImportOutcome, len(quotes))
+	var insertedOrds []int
+	var insertedIDs []int64
+	for matches.Next() {
+		var (
+			ord  int
+			hash string
+			id   int64
+		)
+		if err := matches.Scan(&ord, &hash, &id); err != nil {
+			matches.Close()
+			return nil, err
+		}
+		if inserted[hash] == id {
+			// The first row of the batch with this text is the inserted one.
+			delete(inserted, hash)
+			insertedOrds = append(insertedOrds, ord)
+			insertedIDs = append(insertedIDs, id)
+			outcomes[ord] = ImportOutcome{ID: id, ContentHash: hash}
+			continue
+		}
+		outcomes[ord] = ImportOutcome{Duplicate: true, ID: id, ContentHash: hash}
+	}
+	matches.Close()
+	if err := matches.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(insertedIDs) > 0 {
+		if err := finishImported(ctx, tx, insertedOrds, insertedIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		// Ids of quotes inserted by this batch are rolled back, including for
+		// the duplicates pointing at them.
+		rolledBack := make(map[int64]bool, len(insertedIDs))
+		for _, id := range insertedIDs {
+			rolledBack[id] = true
+		}
+		for i := range outcomes {
+			if rolledBack[outcomes[i].ID] {
+				outcomes[i].ID = 0
+			}
+		}
+		return outcomes, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// insertImported inserts the staged quotes in batch order and returns the ids
+// of the inserted ones by content hash.
+func insertImported(ctx context.Context, tx pgx.Tx) (map[string]int64, error) {
+	rows, err := tx.Query(ctx,
+		`INSERT INTO quotes (author_id, author, quote, lang, created_by, created_at, updated_at)
+		 SELECT author_id, author, quote, lang, created_by, NOW(), NOW()
+		 FROM import_quotes ORDER BY ord
+		 ON CONFLICT (content_hash) WHERE deleted_at IS NULL DO NOTHING
+		 RETURNING content_hash, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inserted := make(map[string]int64)
+	for rows.Next() {
+		var (
+			hash string
+			id   int64
+		)
+		if err := rows.Scan(&hash, &id); err != nil {
+			return nil, err
+		}
+		inserted[hash] = id
+	}
+	return inserted, rows.Err()
+}
+
+// finishImported attaches tags to the inserted quotes and records their first
+// revision and audit event, as AddQuote does for a single quote.
+func finishImported(ctx context.Context, tx pgx.Tx, ords []int, ids []int64) error {
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO tags (name)
+		 SELECT DISTINCT unnest(tags) FROM import_quotes WHERE ord = ANY($1)
+		 ON CONFLICT (name) DO NOTHING`, ords); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO quote_tags (quote_id, tag_id)
+		 SELECT i.id, t.id
+		 FROM unnest($1::integer[], $2::bigint[]) AS i (ord, id)
+		 JOIN import_quotes s ON s.ord = i.ord
+		 CROSS JOIN LATERAL unnest(s.tags) AS tag (name)
+		 JOIN tags t ON t.name = tag.name
+		 ON CONFLICT DO NOTHING`, ords, ids); err != nil {
+		return err
+	}
+
+	actor := ActorFromContext(ctx)
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO quote_revisions (quote_id, revision, author_id, author, quote, lang, created_by)
+		 SELECT id, 1, author_id, author, quote, lang, NULLIF($2, '')
+		 FROM quotes WHERE id = ANY($1)`, ids, actor.Subject); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, `SELECT `+quoteColumns+` FROM quotes WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return err
+	}
+	var events [][]any
+	for rows.Next() {
+		q, err := scanQuote(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		after, err := auditJSON(q)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		events = append(events, []any{q.ID, nullIfEmpty(actor.Subject), AuditCreate, after, nullIfEmpty(actor.RequestID)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"audit_events"},
+		[]string{"quote_id", "actor", "action", "after", "request_id"}, pgx.CopyFromRows(events))
+	return err
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
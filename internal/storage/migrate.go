@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/azaliaz/quote-service/migrations"
+)
+
+// migrateLockID is the advisory lock key that serializes DB.Init migrations of
+// replicas starting at the same time. The golang-migrate postgres driver blocks
+// on pg_advisory_lock too, but migrate.Migrate stops waiting after LockTimeout
+// (15 seconds by default) with ErrLockTimeout, while a slow migration can take
+// longer; its ErrLocked is only an in-process flag of the driver. Holding this
+// lock around the whole PostgresMigrate call lets the other replicas wait as
+// long as Config.MigrateLockTimeout allows, with no limit when it is zero.
+const migrateLockID int64 = 0x71756f7465 // "quote"
+
+// migrate applies pending migrations while holding migrateLockID, so replicas
+// wait for the first one and then find nothing left to do.
+func (r *DB) migrate(ctx context.Context) error {
+	if r.config.MigrateLockTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.MigrateLockTimeout)
+		defer cancel()
+	}
+
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrateLockID); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer func() {
+		// ctx may have expired while migrating; the lock must be released anyway.
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrateLockID); err != nil {
+			r.log.Error("failed to release migration lock", slog.String("err", err.Error()))
+			// A session lock outlives Release, so the connection must not go back to the pool.
+			_ = conn.Conn().Close(context.Background())
+		}
+	}()
+
+	r.log.Info("applying migrations")
+	if err := migrations.PostgresMigrate(r.config.UrlPostgres()); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	r.log.Info("migrations applied")
+	return nil
+}
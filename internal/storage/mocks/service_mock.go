@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	storage "github.com/azaliaz/quote-service/internal/storage"
 	gomock "github.com/golang/mock/gomock"
@@ -51,60 +52,316 @@ func (mr *MockQuoteStorageMockRecorder) AddQuote(ctx, quote interface{}) *gomock
 }
 
 // DeleteQuote mocks base method.
-func (m *MockQuoteStorage) DeleteQuote(ctx context.Context, id int64) error {
+func (m *MockQuoteStorage) DeleteQuote(ctx context.Context, id int64, owner string, versions []int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteQuote", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteQuote", ctx, id, owner, versions)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteQuote indicates an expected call of DeleteQuote.
-func (mr *MockQuoteStorageMockRecorder) DeleteQuote(ctx, id interface{}) *gomock.Call {
+func (mr *MockQuoteStorageMockRecorder) DeleteQuote(ctx, id, owner, versions interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQuote", reflect.TypeOf((*MockQuoteStorage)(nil).DeleteQuote), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQuote", reflect.TypeOf((*MockQuoteStorage)(nil).DeleteQuote), ctx, id, owner, versions)
+}
+
+// ExportQuotes mocks base method.
+func (m *MockQuoteStorage) ExportQuotes(ctx context.Context, filter storage.QuoteFilter, fn func(*storage.Quote) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportQuotes", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportQuotes indicates an expected call of ExportQuotes.
+func (mr *MockQuoteStorageMockRecorder) ExportQuotes(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportQuotes", reflect.TypeOf((*MockQuoteStorage)(nil).ExportQuotes), ctx, filter, fn)
 }
 
 // GetAllQuotes mocks base method.
-func (m *MockQuoteStorage) GetAllQuotes(ctx context.Context) ([]*storage.Quote, error) {
+func (m *MockQuoteStorage) GetAllQuotes(ctx context.Context, filter storage.QuoteFilter, page storage.Page) ([]*storage.Quote, *storage.Cursor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllQuotes", ctx)
+	ret := m.ctrl.Call(m, "GetAllQuotes", ctx, filter, page)
 	ret0, _ := ret[0].([]*storage.Quote)
+	ret1, _ := ret[1].(*storage.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllQuotes indicates an expected call of GetAllQuotes.
+func (mr *MockQuoteStorageMockRecorder) GetAllQuotes(ctx, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllQuotes", reflect.TypeOf((*MockQuoteStorage)(nil).GetAllQuotes), ctx, filter, page)
+}
+
+// GetAuditEvents mocks base method.
+func (m *MockQuoteStorage) GetAuditEvents(ctx context.Context, filter storage.AuditFilter, limit, offset int) ([]*storage.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEvents", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]*storage.AuditEvent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllQuotes indicates an expected call of GetAllQuotes.
-func (mr *MockQuoteStorageMockRecorder) GetAllQuotes(ctx interface{}) *gomock.Call {
+// GetAuditEvents indicates an expected call of GetAuditEvents.
+func (mr *MockQuoteStorageMockRecorder) GetAuditEvents(ctx, filter, limit, offset interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllQuotes", reflect.TypeOf((*MockQuoteStorage)(nil).GetAllQuotes), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEvents", reflect.TypeOf((*MockQuoteStorage)(nil).GetAuditEvents), ctx, filter, limit, offset)
 }
 
-// GetQuotesByAuthor mocks base method.
-func (m *MockQuoteStorage) GetQuotesByAuthor(ctx context.Context, author string) ([]*storage.Quote, error) {
+// GetAuthorByID mocks base method.
+func (m *MockQuoteStorage) GetAuthorByID(ctx context.Context, id int64) (*storage.Author, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetQuotesByAuthor", ctx, author)
+	ret := m.ctrl.Call(m, "GetAuthorByID", ctx, id)
+	ret0, _ := ret[0].(*storage.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorByID indicates an expected call of GetAuthorByID.
+func (mr *MockQuoteStorageMockRecorder) GetAuthorByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByID", reflect.TypeOf((*MockQuoteStorage)(nil).GetAuthorByID), ctx, id)
+}
+
+// GetAuthors mocks base method.
+func (m *MockQuoteStorage) GetAuthors(ctx context.Context, limit, offset int) ([]*storage.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthors", ctx, limit, offset)
+	ret0, _ := ret[0].([]*storage.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthors indicates an expected call of GetAuthors.
+func (mr *MockQuoteStorageMockRecorder) GetAuthors(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthors", reflect.TypeOf((*MockQuoteStorage)(nil).GetAuthors), ctx, limit, offset)
+}
+
+// GetDailyQuote mocks base method.
+func (m *MockQuoteStorage) GetDailyQuote(ctx context.Context, day time.Time) (*storage.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyQuote", ctx, day)
+	ret0, _ := ret[0].(*storage.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyQuote indicates an expected call of GetDailyQuote.
+func (mr *MockQuoteStorageMockRecorder) GetDailyQuote(ctx, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyQuote", reflect.TypeOf((*MockQuoteStorage)(nil).GetDailyQuote), ctx, day)
+}
+
+// GetDeletedQuoteByID mocks base method.
+func (m *MockQuoteStorage) GetDeletedQuoteByID(ctx context.Context, id int64) (*storage.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedQuoteByID", ctx, id)
+	ret0, _ := ret[0].(*storage.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedQuoteByID indicates an expected call of GetDeletedQuoteByID.
+func (mr *MockQuoteStorageMockRecorder) GetDeletedQuoteByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedQuoteByID", reflect.TypeOf((*MockQuoteStorage)(nil).GetDeletedQuoteByID), ctx, id)
+}
+
+// GetDeletedQuotes mocks base method.
+func (m *MockQuoteStorage) GetDeletedQuotes(ctx context.Context, limit, offset int) ([]*storage.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedQuotes", ctx, limit, offset)
 	ret0, _ := ret[0].([]*storage.Quote)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetDeletedQuotes indicates an expected call of GetDeletedQuotes.
+func (mr *MockQuoteStorageMockRecorder) GetDeletedQuotes(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedQuotes", reflect.TypeOf((*MockQuoteStorage)(nil).GetDeletedQuotes), ctx, limit, offset)
+}
+
+// GetQuoteByID mocks base method.
+func (m *MockQuoteStorage) GetQuoteByID(ctx context.Context, id int64) (*storage.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuoteByID", ctx, id)
+	ret0, _ := ret[0].(*storage.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuoteByID indicates an expected call of GetQuoteByID.
+func (mr *MockQuoteStorageMockRecorder) GetQuoteByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuoteByID", reflect.TypeOf((*MockQuoteStorage)(nil).GetQuoteByID), ctx, id)
+}
+
+// GetQuoteRevisions mocks base method.
+func (m *MockQuoteStorage) GetQuoteRevisions(ctx context.Context, id int64) ([]*storage.QuoteRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuoteRevisions", ctx, id)
+	ret0, _ := ret[0].([]*storage.QuoteRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuoteRevisions indicates an expected call of GetQuoteRevisions.
+func (mr *MockQuoteStorageMockRecorder) GetQuoteRevisions(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuoteRevisions", reflect.TypeOf((*MockQuoteStorage)(nil).GetQuoteRevisions), ctx, id)
+}
+
+// GetQuotesByAuthor mocks base method.
+func (m *MockQuoteStorage) GetQuotesByAuthor(ctx context.Context, author string, page storage.Page) ([]*storage.Quote, *storage.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotesByAuthor", ctx, author, page)
+	ret0, _ := ret[0].([]*storage.Quote)
+	ret1, _ := ret[1].(*storage.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetQuotesByAuthor indicates an expected call of GetQuotesByAuthor.
-func (mr *MockQuoteStorageMockRecorder) GetQuotesByAuthor(ctx, author interface{}) *gomock.Call {
+func (mr *MockQuoteStorageMockRecorder) GetQuotesByAuthor(ctx, author, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotesByAuthor", reflect.TypeOf((*MockQuoteStorage)(nil).GetQuotesByAuthor), ctx, author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotesByAuthor", reflect.TypeOf((*MockQuoteStorage)(nil).GetQuotesByAuthor), ctx, author, page)
 }
 
 // GetRandomQuote mocks base method.
-func (m *MockQuoteStorage) GetRandomQuote(ctx context.Context) (*storage.Quote, error) {
+func (m *MockQuoteStorage) GetRandomQuote(ctx context.Context, filter storage.QuoteFilter) (*storage.Quote, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRandomQuote", ctx)
+	ret := m.ctrl.Call(m, "GetRandomQuote", ctx, filter)
 	ret0, _ := ret[0].(*storage.Quote)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRandomQuote indicates an expected call of GetRandomQuote.
-func (mr *MockQuoteStorageMockRecorder) GetRandomQuote(ctx interface{}) *gomock.Call {
+func (mr *MockQuoteStorageMockRecorder) GetRandomQuote(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRandomQuote", reflect.TypeOf((*MockQuoteStorage)(nil).GetRandomQuote), ctx, filter)
+}
+
+// GetTags mocks base method.
+func (m *MockQuoteStorage) GetTags(ctx context.Context) ([]*storage.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTags", ctx)
+	ret0, _ := ret[0].([]*storage.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTags indicates an expected call of GetTags.
+func (mr *MockQuoteStorageMockRecorder) GetTags(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTags", reflect.TypeOf((*MockQuoteStorage)(nil).GetTags), ctx)
+}
+
+// ImportQuotes mocks base method.
+func (m *MockQuoteStorage) ImportQuotes(ctx context.Context, quotes []*storage.Quote, dryRun bool) ([]storage.ImportOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportQuotes", ctx, quotes, dryRun)
+	ret0, _ := ret[0].([]storage.ImportOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportQuotes indicates an expected call of ImportQuotes.
+func (mr *MockQuoteStorageMockRecorder) ImportQuotes(ctx, quotes, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportQuotes", reflect.TypeOf((*MockQuoteStorage)(nil).ImportQuotes), ctx, quotes, dryRun)
+}
+
+// PickDailyQuote mocks base method.
+func (m *MockQuoteStorage) PickDailyQuote(ctx context.Context, day time.Time) (*storage.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PickDailyQuote", ctx, day)
+	ret0, _ := ret[0].(*storage.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PickDailyQuote indicates an expected call of PickDailyQuote.
+func (mr *MockQuoteStorageMockRecorder) PickDailyQuote(ctx, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickDailyQuote", reflect.TypeOf((*MockQuoteStorage)(nil).PickDailyQuote), ctx, day)
+}
+
+// PurgeDeletedQuotes mocks base method.
+func (m *MockQuoteStorage) PurgeDeletedQuotes(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedQuotes", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedQuotes indicates an expected call of PurgeDeletedQuotes.
+func (mr *MockQuoteStorageMockRecorder) PurgeDeletedQuotes(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedQuotes", reflect.TypeOf((*MockQuoteStorage)(nil).PurgeDeletedQuotes), ctx, before)
+}
+
+// RestoreQuote mocks base method.
+func (m *MockQuoteStorage) RestoreQuote(ctx context.Context, id int64, owner string, versions []int) (*storage.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreQuote", ctx, id, owner, versions)
+	ret0, _ := ret[0].(*storage.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreQuote indicates an expected call of RestoreQuote.
+func (mr *MockQuoteStorageMockRecorder) RestoreQuote(ctx, id, owner, versions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreQuote", reflect.TypeOf((*MockQuoteStorage)(nil).RestoreQuote), ctx, id, owner, versions)
+}
+
+// RevertQuote mocks base method.
+func (m *MockQuoteStorage) RevertQuote(ctx context.Context, id int64, revision int, versions []int) (*storage.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertQuote", ctx, id, revision, versions)
+	ret0, _ := ret[0].(*storage.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertQuote indicates an expected call of RevertQuote.
+func (mr *MockQuoteStorageMockRecorder) RevertQuote(ctx, id, revision, versions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertQuote", reflect.TypeOf((*MockQuoteStorage)(nil).RevertQuote), ctx, id, revision, versions)
+}
+
+// SearchQuotes mocks base method.
+func (m *MockQuoteStorage) SearchQuotes(ctx context.Context, query string, limit, offset int) ([]*storage.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchQuotes", ctx, query, limit, offset)
+	ret0, _ := ret[0].([]*storage.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchQuotes indicates an expected call of SearchQuotes.
+func (mr *MockQuoteStorageMockRecorder) SearchQuotes(ctx, query, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchQuotes", reflect.TypeOf((*MockQuoteStorage)(nil).SearchQuotes), ctx, query, limit, offset)
+}
+
+// UpdateQuote mocks base method.
+func (m *MockQuoteStorage) UpdateQuote(ctx context.Context, id int64, upd *storage.QuoteUpdate) (*storage.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQuote", ctx, id, upd)
+	ret0, _ := ret[0].(*storage.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateQuote indicates an expected call of UpdateQuote.
+func (mr *MockQuoteStorageMockRecorder) UpdateQuote(ctx, id, upd interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRandomQuote", reflect.TypeOf((*MockQuoteStorage)(nil).GetRandomQuote), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuote", reflect.TypeOf((*MockQuoteStorage)(nil).UpdateQuote), ctx, id, upd)
 }
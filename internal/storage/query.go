@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryArgs collects positional arguments while a query is being assembled.
+type queryArgs []any
+
+// add appends v and returns its placeholder.
+func (a *queryArgs) add(v any) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// liveCondition excludes quotes that are in the trash.
+const liveCondition = "quotes.deleted_at IS NULL"
+
+// filterConditions turns a QuoteFilter into WHERE conditions on the quotes table.
+func filterConditions(filter QuoteFilter, args *queryArgs) []string {
+	var conds []string
+	if filter.Author != "" {
+		conds = append(conds, "quotes.author_id = (SELECT author_id FROM author_aliases WHERE alias = "+
+			aliasKey(args.add(filter.Author))+")")
+	}
+	if filter.AuthorID != 0 {
+		conds = append(conds, "quotes.author_id = "+args.add(filter.AuthorID))
+	}
+	if filter.Lang != "" {
+		conds = append(conds, "quotes.lang = "+args.add(filter.Lang))
+	}
+	if len(filter.Tags) > 0 {
+		matched := `SELECT 1 FROM quote_tags qt JOIN tags t ON t.id = qt.tag_id
+			WHERE qt.quote_id = quotes.id AND t.name = ANY(` + args.add(filter.Tags) + `)`
+		if filter.MatchAllTags {
+			conds = append(conds, fmt.Sprintf("(SELECT count(*) FROM (%s) m) = %s", matched, args.add(len(filter.Tags))))
+		} else {
+			conds = append(conds, "EXISTS ("+matched+")")
+		}
+	}
+	return conds
+}
+
+func where(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(conds, " AND ")
+}
+
+// aliasKey normalizes an author spelling the same way author_aliases.alias is stored.
+func aliasKey(expr string) string {
+	return "lower(btrim(" + expr + "))"
+}
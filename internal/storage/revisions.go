@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// recordRevision stores the current content of q as its next revision inside tx.
+// Callers must hold a lock on the quote row so revision numbers stay gapless.
+func recordRevision(ctx context.Context, tx pgx.Tx, q *Quote) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO quote_revisions (quote_id, revision, author_id, author, quote, lang, created_by)
+		 SELECT $1, COALESCE(max(revision), 0) + 1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, '')
+		 FROM quote_revisions WHERE quote_id = $1`,
+		q.ID, q.AuthorID, q.Author, q.Quote, q.Lang, ActorFromContext(ctx).Subject)
+	return err
+}
+
+// GetQuoteRevisions lists the revisions of a live quote, newest first.
+func (db *DB) GetQuoteRevisions(ctx context.Context, id int64) ([]*QuoteRevision, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	var exists bool
+	if err := conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM quotes WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	rows, err := conn.Query(ctx,
+		`SELECT quote_id, revision, author_id, author, quote, COALESCE(lang, ''), COALESCE(created_by, ''), created_at
+		 FROM quote_revisions
+		 WHERE quote_id = $1
+		 ORDER BY revision DESC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*QuoteRevision
+	for rows.Next() {
+		var r QuoteRevision
+		if err := rows.Scan(&r.QuoteID, &r.Revision, &r.AuthorID, &r.Author, &r.Quote, &r.Lang, &r.CreatedBy, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &r)
+	}
+	return revisions, rows.Err()
+}
+
+// RevertQuote sets the content of a live quote back to the given revision and
+// records the result as a new revision. It fails with ErrConflict when another
+// quote has the same text by now. Versions works like in QuoteUpdate.
+func (db *DB) RevertQuote(ctx context.Context, id int64, revision int, versions []int) (*Quote, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			db.log.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	before, err := getQuoteTx(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if before.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	if err := matchVersion(before, versions); err != nil {
+		return nil, err
+	}
+
+	q, err := scanQuote(tx.QueryRow(ctx,
+		`UPDATE quotes
+		 SET author_id = r.author_id,
+		     author = r.author,
+		     quote = r.quote,
+		     lang = r.lang,
+		     version = quotes.version + 1,
+		     updated_at = NOW()
+		 FROM quote_revisions r
+		 WHERE quotes.id = $1 AND r.quote_id = quotes.id AND r.revision = $2
+		 RETURNING `+quoteColumns, id, revision))
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if err := recordRevision(ctx, tx, q); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, AuditRevert, id, before, q); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
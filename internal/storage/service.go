@@ -12,17 +12,114 @@ import (
 
 type QuoteStorage interface {
 	AddQuote(ctx context.Context, quote *Quote) (int64, error)
-	GetAllQuotes(ctx context.Context) ([]*Quote, error)
-	GetRandomQuote(ctx context.Context) (*Quote, error)
-	GetQuotesByAuthor(ctx context.Context, author string) ([]*Quote, error)
-	DeleteQuote(ctx context.Context, id int64) error
+	GetAllQuotes(ctx context.Context, filter QuoteFilter, page Page) ([]*Quote, *Cursor, error)
+	GetRandomQuote(ctx context.Context, filter QuoteFilter) (*Quote, error)
+	GetQuoteByID(ctx context.Context, id int64) (*Quote, error)
+	GetQuotesByAuthor(ctx context.Context, author string, page Page) ([]*Quote, *Cursor, error)
+	UpdateQuote(ctx context.Context, id int64, upd *QuoteUpdate) (*Quote, error)
+	DeleteQuote(ctx context.Context, id int64, owner string, versions []int) error
+	SearchQuotes(ctx context.Context, query string, limit, offset int) ([]*SearchResult, error)
+	GetTags(ctx context.Context) ([]*Tag, error)
+	GetAuthors(ctx context.Context, limit, offset int) ([]*Author, error)
+	GetAuthorByID(ctx context.Context, id int64) (*Author, error)
+	GetDailyQuote(ctx context.Context, day time.Time) (*Quote, error)
+	PickDailyQuote(ctx context.Context, day time.Time) (*Quote, error)
+	GetDeletedQuotes(ctx context.Context, limit, offset int) ([]*Quote, error)
+	GetDeletedQuoteByID(ctx context.Context, id int64) (*Quote, error)
+	RestoreQuote(ctx context.Context, id int64, owner string, versions []int) (*Quote, error)
+	PurgeDeletedQuotes(ctx context.Context, before time.Time) (int64, error)
+	GetAuditEvents(ctx context.Context, filter AuditFilter, limit, offset int) ([]*AuditEvent, error)
+	GetQuoteRevisions(ctx context.Context, id int64) ([]*QuoteRevision, error)
+	RevertQuote(ctx context.Context, id int64, revision int, versions []int) (*Quote, error)
+	ImportQuotes(ctx context.Context, quotes []*Quote, dryRun bool) ([]ImportOutcome, error)
+	ExportQuotes(ctx context.Context, filter QuoteFilter, fn func(*Quote) error) error
 }
+
 type Quote struct {
 	ID        int64
+	AuthorID  int64
+	Author    string
+	Quote     string
+	Lang      string
+	CreatedBy string
+	// Version starts at 1 and grows with every change to the quote.
+	Version   int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+	Tags      []string
+}
+
+// QuoteFilter narrows list queries. Zero fields do not filter.
+// Author is resolved through author aliases; AuthorID matches the author directly.
+// Lang matches the quote language code exactly.
+// Tags match quotes having any of the tags, or all of them with MatchAllTags.
+type QuoteFilter struct {
+	Author       string
+	AuthorID     int64
+	Lang         string
+	Tags         []string
+	MatchAllTags bool
+}
+
+// Tag is a tag name with the number of quotes it is attached to.
+type Tag struct {
+	Name  string
+	Count int64
+}
+
+// Author is a canonical author together with the spellings that resolve to it.
+type Author struct {
+	ID        int64
+	Name      string
+	Aliases   []string
+	Bio       string
+	BirthYear *int
+	DeathYear *int
+}
+
+// QuoteRevision is one stored version of a quote's content. CreatedBy is the
+// actor that made the change.
+type QuoteRevision struct {
+	QuoteID   int64
+	Revision  int
+	AuthorID  int64
 	Author    string
 	Quote     string
+	Lang      string
+	CreatedBy string
 	CreatedAt time.Time
 }
+
+// QuoteUpdate lists the fields to change; nil fields keep their current value.
+// When Versions is not empty the quote must be at one of them, otherwise
+// ErrVersionMismatch is returned.
+type QuoteUpdate struct {
+	Author   *string
+	Quote    *string
+	Versions []int
+}
+
+// SearchResult is a quote matched by full-text search together with its rank
+// and a highlighted fragment of the quote text.
+type SearchResult struct {
+	Quote
+	Rank     float32
+	Headline string
+}
+
+// Cursor points at a row in the (created_at, id) ordering used by the list queries.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// Page describes a keyset page: up to Limit rows strictly after After.
+type Page struct {
+	Limit int
+	After *Cursor
+}
+
 type AddQuoteRequest struct {
 	Author string
 	Quote  string
@@ -71,6 +168,13 @@ func (r *DB) Init() error {
 
 	r.pool = pool
 
+	if r.config.AutoMigrate {
+		if err := r.migrate(ctx); err != nil {
+			pool.Close()
+			return err
+		}
+	}
+
 	r.log.Info("connected to postgres")
 	return nil
 }
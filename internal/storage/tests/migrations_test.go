@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+	"github.com/azaliaz/quote-service/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaQuery describes every column, constraint, index and function of the
+// public schema, leaving out the migration bookkeeping table.
+const schemaQuery = `
+SELECT 'column ' || table_name || '.' || column_name || ' ' || data_type || ' ' ||
+       is_nullable || ' ' || coalesce(column_default, '') || ' ' || coalesce(generation_expression, '')
+FROM information_schema.columns
+WHERE table_schema = 'public' AND table_name <> 'schema_migrations'
+UNION ALL
+SELECT 'constraint ' || conrelid::regclass || ' ' || conname || ' ' || pg_get_constraintdef(oid)
+FROM pg_constraint
+WHERE connamespace = 'public'::regnamespace AND conrelid <> 'schema_migrations'::regclass
+UNION ALL
+SELECT 'index ' || indexdef
+FROM pg_indexes
+WHERE schemaname = 'public' AND tablename <> 'schema_migrations'
+UNION ALL
+SELECT 'function ' || p.oid::regprocedure
+FROM pg_proc p
+WHERE p.pronamespace = 'public'::regnamespace
+ORDER BY 1`
+
+func schema(ctx context.Context, t *testing.T, conn *pgx.Conn) []string {
+	rows, err := conn.Query(ctx, schemaQuery)
+	require.NoError(t, err)
+	objects, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	require.NoError(t, err)
+	return objects
+}
+
+// TestMigrationsUpDownUp checks that every migration has a working down file:
+// rolling everything back leaves an empty schema and migrating up again
+// recreates the same one.
+func TestMigrationsUpDownUp(t *testing.T) {
+	ctx := context.Background()
+	container, cfg := startPostgres(ctx, t)
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Logf("failed to terminate container: %v", err)
+		}
+	})
+
+	conn, err := pgx.Connect(ctx, cfg.UrlPostgres())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close(context.Background()) })
+
+	require.NoError(t, migrations.PostgresMigrate(cfg.UrlPostgres()))
+	migrated := schema(ctx, t, conn)
+	require.NotEmpty(t, migrated)
+
+	m, err := migrations.NewPostgresMigrator(cfg.UrlPostgres())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	latest, dirty, err := m.Version()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	assert.ErrorIs(t, m.Up(), migrate.ErrNoChange)
+
+	// Step down one migration and back through the planned steps.
+	planned, err := m.PlanDown(1)
+	require.NoError(t, err)
+	require.Len(t, planned, 1)
+	assert.Equal(t, latest, planned[0].Version)
+	assert.Equal(t, migrations.Down, planned[0].Direction)
+	require.NoError(t, m.Down(1))
+	pending, err := m.PlanUp()
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, latest, pending[0].Version)
+	require.NoError(t, m.Goto(latest))
+	assert.Equal(t, migrated, schema(ctx, t, conn))
+
+	require.NoError(t, migrations.PostgresMigrateDown(cfg.UrlPostgres()))
+	assert.Empty(t, schema(ctx, t, conn))
+	_, _, err = m.Version()
+	assert.True(t, errors.Is(err, migrate.ErrNilVersion), "expected no version after rolling back, got %v", err)
+
+	require.NoError(t, migrations.PostgresMigrate(cfg.UrlPostgres()))
+	assert.Equal(t, migrated, schema(ctx, t, conn))
+}
+
+// TestAutoMigrate starts several replicas at once against an empty database:
+// one of them migrates while the others wait for the lock and find nothing to do.
+func TestAutoMigrate(t *testing.T) {
+	ctx := context.Background()
+	container, cfg := startPostgres(ctx, t)
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Logf("failed to terminate container: %v", err)
+		}
+	})
+	cfg.AutoMigrate = true
+	cfg.MigrateLockTimeout = time.Minute
+
+	const replicas = 4
+	errs := make(chan error, replicas)
+	for i := 0; i < replicas; i++ {
+		go func() {
+			db := storage.NewDB(&cfg, slog.Default())
+			err := db.Init()
+			if err == nil {
+				defer db.Stop()
+				_, _, err = db.GetAllQuotes(ctx, storage.QuoteFilter{}, storage.Page{Limit: 1})
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < replicas; i++ {
+		assert.NoError(t, <-errs)
+	}
+
+	m, err := migrations.NewPostgresMigrator(cfg.UrlPostgres())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+	_, dirty, err := m.Version()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	pending, err := m.PlanUp()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
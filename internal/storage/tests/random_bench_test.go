@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/azaliaz/quote-service/internal/storage"
+	"github.com/azaliaz/quote-service/migrations"
+	"github.com/stretchr/testify/require"
+)
+
+const benchQuotes = 1_000_000
+
+// BenchmarkGetRandomQuote compares id sampling with the ORDER BY RANDOM() query
+// it replaced on a table of 1M quotes with every tenth id deleted. Filtered
+// shows the cost of count and OFFSET for a tag on every hundredth quote:
+//
+//	go test -run '^$' -bench GetRandomQuote -benchtime 200x ./internal/storage/tests
+func BenchmarkGetRandomQuote(b *testing.B) {
+	ctx := context.Background()
+	container, cfg := startPostgres(ctx, b)
+	b.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			b.Logf("failed to terminate container: %v", err)
+		}
+	})
+
+	require.NoError(b, migrations.PostgresMigrate(cfg.UrlPostgres()))
+	db := storage.NewDB(&cfg, slog.Default())
+	require.NoError(b, db.Init())
+	b.Cleanup(db.Stop)
+
+	for _, stmt := range []string{
+		`INSERT INTO authors (name) VALUES ('Bench')`,
+		`INSERT INTO author_aliases (alias, author_id) SELECT 'bench', id FROM authors`,
+		`INSERT INTO quotes (author_id, author, quote)
+		 SELECT a.id, a.name, 'Quote number ' || g
+		 FROM authors a, generate_series(1, ` + strconv.Itoa(benchQuotes) + `) AS g`,
+		`DELETE FROM quotes WHERE id % 10 = 0`,
+		`INSERT INTO tags (name) VALUES ('rare')`,
+		`INSERT INTO quote_tags (quote_id, tag_id)
+		 SELECT q.id, t.id FROM quotes q, tags t WHERE q.id % 100 = 1`,
+		`ANALYZE quotes`,
+	} {
+		_, err := db.Pool().Exec(ctx, stmt)
+		require.NoError(b, err)
+	}
+
+	b.Run("IDSampling", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := db.GetRandomQuote(ctx, storage.QuoteFilter{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Filtered", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := db.GetRandomQuote(ctx, storage.QuoteFilter{Tags: []string{"rare"}}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("OrderByRandom", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var id int64
+			if err := db.Pool().QueryRow(ctx,
+				`SELECT id FROM quotes ORDER BY RANDOM() LIMIT 1`).Scan(&id); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -2,6 +2,9 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/azaliaz/quote-service/internal/storage"
 	"github.com/azaliaz/quote-service/migrations"
 	"github.com/stretchr/testify/assert"
@@ -52,6 +55,14 @@ func (s *QuoteRepositoryTestSuite) TearDownTest() {
 }
 
 func (s *QuoteRepositoryTestSuite) setupPostgres(ctx context.Context) storage.Config {
+	pgContainer, cfg := startPostgres(ctx, s.T())
+	s.container = pgContainer
+	s.dbConfig = cfg
+	return cfg
+}
+
+// startPostgres runs a throwaway Postgres container and returns a config pointing at it.
+func startPostgres(ctx context.Context, tb testing.TB) (*postgres.PostgresContainer, storage.Config) {
 	cfg := storage.Config{
 		Host:             "",
 		DbName:           "test-db",
@@ -71,18 +82,16 @@ func (s *QuoteRepositoryTestSuite) setupPostgres(ctx context.Context) storage.Co
 				WithOccurrence(2).WithStartupTimeout(5*time.Second)),
 	)
 
-	require.NoError(s.T(), err)
-	s.container = pgContainer
+	require.NoError(tb, err)
 
 	host, err := pgContainer.Host(ctx)
-	require.NoError(s.T(), err)
+	require.NoError(tb, err)
 	cfg.Host = host
 	ports, err := pgContainer.MappedPort(ctx, "5432")
-	require.NoError(s.T(), err)
+	require.NoError(tb, err)
 	cfg.Host += ":" + strconv.Itoa(ports.Int())
 
-	s.dbConfig = cfg
-	return cfg
+	return pgContainer, cfg
 }
 
 func (s *QuoteRepositoryTestSuite) TearDownSuite() {
@@ -103,7 +112,7 @@ func (s *QuoteRepositoryTestSuite) TestAddQuoteAndGetAllQuotes() {
 	require.NoError(s.T(), err)
 	assert.Greater(s.T(), id, int64(0))
 
-	quotes, err := s.repo.GetAllQuotes(ctx)
+	quotes, _, err := s.repo.GetAllQuotes(ctx, storage.QuoteFilter{}, storage.Page{Limit: 100})
 	require.NoError(s.T(), err)
 	require.NotEmpty(s.T(), quotes)
 	found := false
@@ -124,12 +133,86 @@ func (s *QuoteRepositoryTestSuite) TestGetRandomQuote() {
 	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "B", Quote: "B quote"})
 	require.NoError(s.T(), err)
 
-	q, err := s.repo.GetRandomQuote(ctx)
+	q, err := s.repo.GetRandomQuote(ctx, storage.QuoteFilter{})
 	require.NoError(s.T(), err)
 	require.NotNil(s.T(), q)
 	assert.Contains(s.T(), []string{"A", "B"}, q.Author)
 }
 
+func (s *QuoteRepositoryTestSuite) TestGetRandomQuote_Filtered() {
+	ctx := context.Background()
+	_, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Seneca", Quote: "While we wait for life, life passes.", Lang: "en", Tags: []string{"time"}})
+	require.NoError(s.T(), err)
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Seneca", Quote: "Пока откладываем, жизнь проходит.", Lang: "ru", Tags: []string{"time"}})
+	require.NoError(s.T(), err)
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Mark Twain", Quote: "The secret of getting ahead is getting started.", Lang: "en", Tags: []string{"motivation"}})
+	require.NoError(s.T(), err)
+
+	for i := 0; i < 10; i++ {
+		q, err := s.repo.GetRandomQuote(ctx, storage.QuoteFilter{Author: "seneca", Lang: "ru"})
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), "ru", q.Lang)
+	}
+
+	q, err := s.repo.GetRandomQuote(ctx, storage.QuoteFilter{Tags: []string{"motivation"}})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "Mark Twain", q.Author)
+
+	_, err = s.repo.GetRandomQuote(ctx, storage.QuoteFilter{Author: "Mark Twain", Tags: []string{"time"}})
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+}
+
+func (s *QuoteRepositoryTestSuite) TestGetRandomQuote_FilteredSpread() {
+	ctx := context.Background()
+	// Untagged quotes leave gaps of very different sizes between the tagged
+	// ones, which would skew any pick driven by the id range.
+	gaps := []int{0, 3, 15, 60}
+	counts := make(map[int64]int, len(gaps))
+	for i, gap := range gaps {
+		for j := 0; j < gap; j++ {
+			_, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Filler", Quote: fmt.Sprintf("Filler %d-%d", i, j)})
+			require.NoError(s.T(), err)
+		}
+		id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Seneca", Quote: fmt.Sprintf("Rare %d", i), Tags: []string{"rare"}})
+		require.NoError(s.T(), err)
+		counts[id] = 0
+	}
+
+	const picks = 400
+	for i := 0; i < picks; i++ {
+		q, err := s.repo.GetRandomQuote(ctx, storage.QuoteFilter{Tags: []string{"rare"}})
+		require.NoError(s.T(), err)
+		require.Contains(s.T(), counts, q.ID)
+		counts[q.ID]++
+	}
+
+	for id, n := range counts {
+		assert.InDelta(s.T(), picks/len(gaps), n, 50, "quote %d picked %d times", id, n)
+	}
+}
+
+func (s *QuoteRepositoryTestSuite) TestGetQuoteByID() {
+	ctx := context.Background()
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Oscar Wilde", Quote: "Be yourself; everyone else is already taken."})
+	require.NoError(s.T(), err)
+
+	q, err := s.repo.GetQuoteByID(ctx, id)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), id, q.ID)
+	assert.Equal(s.T(), "Oscar Wilde", q.Author)
+	assert.Empty(s.T(), q.CreatedBy)
+
+	ownedID, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Oscar Wilde", Quote: "I can resist everything except temptation.", CreatedBy: "alice"})
+	require.NoError(s.T(), err)
+	q, err = s.repo.GetQuoteByID(ctx, ownedID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "alice", q.CreatedBy)
+
+	q, err = s.repo.GetQuoteByID(ctx, id+1000)
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+	assert.Nil(s.T(), q)
+}
+
 func (s *QuoteRepositoryTestSuite) TestGetQuotesByAuthor() {
 	ctx := context.Background()
 	_, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "AuthorX", Quote: "Quote 1"})
@@ -137,7 +220,7 @@ func (s *QuoteRepositoryTestSuite) TestGetQuotesByAuthor() {
 	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Other", Quote: "Quote 2"})
 	require.NoError(s.T(), err)
 
-	quotes, err := s.repo.GetQuotesByAuthor(ctx, "AuthorX")
+	quotes, _, err := s.repo.GetQuotesByAuthor(ctx, "AuthorX", storage.Page{Limit: 100})
 	require.NoError(s.T(), err)
 	require.NotEmpty(s.T(), quotes)
 	for _, q := range quotes {
@@ -150,40 +233,540 @@ func (s *QuoteRepositoryTestSuite) TestDeleteQuote() {
 	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "ToDelete", Quote: "Delete me"})
 	require.NoError(s.T(), err)
 
-	err = s.repo.DeleteQuote(ctx, id)
+	err = s.repo.DeleteQuote(ctx, id, "", nil)
 	require.NoError(s.T(), err)
 
-	quotes, err := s.repo.GetAllQuotes(ctx)
+	quotes, _, err := s.repo.GetAllQuotes(ctx, storage.QuoteFilter{}, storage.Page{Limit: 100})
 	require.NoError(s.T(), err)
 	for _, q := range quotes {
 		assert.NotEqual(s.T(), id, q.ID)
 	}
 
-	err = s.repo.DeleteQuote(ctx, id)
-	assert.Error(s.T(), err)
+	err = s.repo.DeleteQuote(ctx, id, "", nil)
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+}
+
+func (s *QuoteRepositoryTestSuite) TestDeleteQuote_Owner() {
+	ctx := context.Background()
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Owned", Quote: "Only Alice may delete me", CreatedBy: "alice"})
+	require.NoError(s.T(), err)
+
+	assert.ErrorIs(s.T(), s.repo.DeleteQuote(ctx, id, "bob", nil), storage.ErrNotOwner)
+	require.NoError(s.T(), s.repo.DeleteQuote(ctx, id, "alice", nil))
+
+	_, err = s.repo.RestoreQuote(ctx, id, "bob", nil)
+	assert.ErrorIs(s.T(), err, storage.ErrNotOwner)
+	restored, err := s.repo.RestoreQuote(ctx, id, "alice", nil)
+	require.NoError(s.T(), err)
+	assert.Nil(s.T(), restored.DeletedAt)
+}
+
+func (s *QuoteRepositoryTestSuite) TestTrash() {
+	ctx := context.Background()
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Trashed", Quote: "Trash me"})
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.repo.DeleteQuote(ctx, id, "", nil))
+
+	_, err = s.repo.GetQuoteByID(ctx, id)
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+	_, err = s.repo.GetRandomQuote(ctx, storage.QuoteFilter{})
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+	byAuthor, _, err := s.repo.GetQuotesByAuthor(ctx, "Trashed", storage.Page{Limit: 10})
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), byAuthor)
+
+	trash, err := s.repo.GetDeletedQuotes(ctx, 10, 0)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), trash, 1)
+	assert.Equal(s.T(), id, trash[0].ID)
+	assert.NotNil(s.T(), trash[0].DeletedAt)
+
+	restored, err := s.repo.RestoreQuote(ctx, id, "", nil)
+	require.NoError(s.T(), err)
+	assert.Nil(s.T(), restored.DeletedAt)
+	_, err = s.repo.RestoreQuote(ctx, id, "", nil)
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+
+	// Re-adding the text of a trashed quote is allowed, restoring the old one is then a conflict.
+	require.NoError(s.T(), s.repo.DeleteQuote(ctx, id, "", nil))
+	newID, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Trashed", Quote: "Trash me"})
+	require.NoError(s.T(), err)
+	assert.NotEqual(s.T(), id, newID)
+	_, err = s.repo.RestoreQuote(ctx, id, "", nil)
+	assert.ErrorIs(s.T(), err, storage.ErrConflict)
+
+	purged, err := s.repo.PurgeDeletedQuotes(ctx, time.Now().Add(-time.Hour))
+	require.NoError(s.T(), err)
+	assert.Zero(s.T(), purged)
+	purged, err = s.repo.PurgeDeletedQuotes(ctx, time.Now().Add(time.Hour))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), purged)
+	_, err = s.repo.GetDeletedQuoteByID(ctx, id)
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+}
+
+func (s *QuoteRepositoryTestSuite) TestAuditEvents() {
+	ctx := storage.WithActor(context.Background(), storage.Actor{Subject: "alice", RequestID: "req-1"})
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Audited", Quote: "Watch me"})
+	require.NoError(s.T(), err)
+	text := "Watch me change"
+	_, err = s.repo.UpdateQuote(ctx, id, &storage.QuoteUpdate{Quote: &text})
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.repo.DeleteQuote(storage.WithActor(context.Background(), storage.Actor{Subject: "bob"}), id, "", nil))
+	_, err = s.repo.RestoreQuote(ctx, id, "", nil)
+	require.NoError(s.T(), err)
+
+	events, err := s.repo.GetAuditEvents(ctx, storage.AuditFilter{QuoteID: id}, 10, 0)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), events, 4)
+	assert.Equal(s.T(), storage.AuditRestore, events[0].Action)
+	assert.Equal(s.T(), storage.AuditDelete, events[1].Action)
+	assert.Equal(s.T(), storage.AuditUpdate, events[2].Action)
+	assert.Equal(s.T(), storage.AuditCreate, events[3].Action)
+	assert.Nil(s.T(), events[3].Before)
+	assert.Equal(s.T(), "req-1", events[3].RequestID)
+
+	var before, after storage.Quote
+	require.NoError(s.T(), json.Unmarshal(events[2].Before, &before))
+	require.NoError(s.T(), json.Unmarshal(events[2].After, &after))
+	assert.Equal(s.T(), "Watch me", before.Quote)
+	assert.Equal(s.T(), text, after.Quote)
+
+	byBob, err := s.repo.GetAuditEvents(ctx, storage.AuditFilter{Actor: "bob"}, 10, 0)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), byBob, 1)
+	assert.Equal(s.T(), storage.AuditDelete, byBob[0].Action)
+
+	// A failed mutation leaves no event behind.
+	require.Error(s.T(), s.repo.DeleteQuote(ctx, id+100, "", nil))
+	missing, err := s.repo.GetAuditEvents(ctx, storage.AuditFilter{QuoteID: id + 100}, 10, 0)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), missing)
+}
+
+func (s *QuoteRepositoryTestSuite) TestQuoteRevisions() {
+	ctx := storage.WithActor(context.Background(), storage.Actor{Subject: "alice"})
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Reviser", Quote: "First draft"})
+	require.NoError(s.T(), err)
+	text := "Second draft"
+	_, err = s.repo.UpdateQuote(storage.WithActor(context.Background(), storage.Actor{Subject: "bob"}), id,
+		&storage.QuoteUpdate{Quote: &text})
+	require.NoError(s.T(), err)
+
+	revisions, err := s.repo.GetQuoteRevisions(ctx, id)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revisions, 2)
+	assert.Equal(s.T(), 2, revisions[0].Revision)
+	assert.Equal(s.T(), "Second draft", revisions[0].Quote)
+	assert.Equal(s.T(), "bob", revisions[0].CreatedBy)
+	assert.Equal(s.T(), 1, revisions[1].Revision)
+	assert.Equal(s.T(), "alice", revisions[1].CreatedBy)
+
+	reverted, err := s.repo.RevertQuote(ctx, id, 1, nil)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "First draft", reverted.Quote)
+
+	revisions, err = s.repo.GetQuoteRevisions(ctx, id)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revisions, 3)
+	assert.Equal(s.T(), 3, revisions[0].Revision)
+	assert.Equal(s.T(), "First draft", revisions[0].Quote)
+
+	_, err = s.repo.RevertQuote(ctx, id, 9, nil)
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+
+	// Rolling back onto text another quote has taken is a conflict.
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Reviser", Quote: "Second draft"})
+	require.NoError(s.T(), err)
+	_, err = s.repo.RevertQuote(ctx, id, 2, nil)
+	assert.ErrorIs(s.T(), err, storage.ErrConflict)
+
+	require.NoError(s.T(), s.repo.DeleteQuote(ctx, id, "", nil))
+	_, err = s.repo.GetQuoteRevisions(ctx, id)
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+}
+
+func (s *QuoteRepositoryTestSuite) TestQuoteVersion() {
+	ctx := context.Background()
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Versioned", Quote: "Version one"})
+	require.NoError(s.T(), err)
+	q, err := s.repo.GetQuoteByID(ctx, id)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, q.Version)
+
+	text := "Version two"
+	updated, err := s.repo.UpdateQuote(ctx, id, &storage.QuoteUpdate{Quote: &text, Versions: []int{1}})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, updated.Version)
+
+	_, err = s.repo.UpdateQuote(ctx, id, &storage.QuoteUpdate{Quote: &text, Versions: []int{1}})
+	assert.ErrorIs(s.T(), err, storage.ErrVersionMismatch)
+	assert.ErrorIs(s.T(), s.repo.DeleteQuote(ctx, id, "", []int{1}), storage.ErrVersionMismatch)
+
+	require.NoError(s.T(), s.repo.DeleteQuote(ctx, id, "", []int{1, 2}))
+	_, err = s.repo.RestoreQuote(ctx, id, "", []int{2})
+	assert.ErrorIs(s.T(), err, storage.ErrVersionMismatch)
+	restored, err := s.repo.RestoreQuote(ctx, id, "", []int{3})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 4, restored.Version)
+
+	_, err = s.repo.RevertQuote(ctx, id, 1, []int{3})
+	assert.ErrorIs(s.T(), err, storage.ErrVersionMismatch)
+	reverted, err := s.repo.RevertQuote(ctx, id, 1, []int{4})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 5, reverted.Version)
+}
+
+func (s *QuoteRepositoryTestSuite) TestImportQuotes() {
+	ctx := context.Background()
+	existing, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Seneca", Quote: "Already stored"})
+	require.NoError(s.T(), err)
+
+	batch := []*storage.Quote{
+		{Author: "Seneca", Quote: "Imported one", Tags: []string{"stoic"}},
+		{Author: "Seneca", Quote: "Already stored"},
+		{Author: "Marcus Aurelius", Quote: "Imported two", Lang: "en"},
+		{Author: "Seneca", Quote: "Imported one"},
+	}
+
+	dry, err := s.repo.ImportQuotes(ctx, batch, true)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), dry, len(batch))
+	assert.False(s.T(), dry[0].Duplicate)
+	assert.Zero(s.T(), dry[0].ID)
+	assert.True(s.T(), dry[1].Duplicate)
+	assert.Equal(s.T(), existing, dry[1].ID)
+	assert.True(s.T(), dry[3].Duplicate)
+	assert.Zero(s.T(), dry[3].ID)
+	// The hash is what the duplicate check compares, so case and punctuation do not count.
+	assert.Equal(s.T(), dry[0].ContentHash, dry[3].ContentHash)
+	assert.NotEqual(s.T(), dry[0].ContentHash, dry[2].ContentHash)
+
+	quotes, _, err := s.repo.GetAllQuotes(ctx, storage.QuoteFilter{}, storage.Page{Limit: 10})
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), quotes, 1)
+
+	outcomes, err := s.repo.ImportQuotes(ctx, batch, false)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), outcomes, len(batch))
+	assert.NotZero(s.T(), outcomes[0].ID)
+	assert.Equal(s.T(), storage.ImportOutcome{Duplicate: true, ID: existing, ContentHash: dry[1].ContentHash}, outcomes[1])
+	assert.False(s.T(), outcomes[2].Duplicate)
+	assert.Equal(s.T(), storage.ImportOutcome{Duplicate: true, ID: outcomes[0].ID, ContentHash: dry[0].ContentHash}, outcomes[3])
+
+	imported, err := s.repo.GetQuoteByID(ctx, outcomes[0].ID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"stoic"}, imported.Tags)
+	assert.Equal(s.T(), 1, imported.Version)
+
+	revisions, err := s.repo.GetQuoteRevisions(ctx, outcomes[2].ID)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), revisions, 1)
+
+	events, err := s.repo.GetAuditEvents(ctx, storage.AuditFilter{QuoteID: outcomes[2].ID}, 10, 0)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), events, 1)
+	assert.Equal(s.T(), storage.AuditCreate, events[0].Action)
+}
+
+func (s *QuoteRepositoryTestSuite) TestExportQuotes() {
+	ctx := context.Background()
+	batch := make([]*storage.Quote, 0, 600)
+	for i := 0; i < 600; i++ {
+		q := &storage.Quote{Author: "Exporter", Quote: fmt.Sprintf("Exported quote %d", i)}
+		if i%3 == 0 {
+			q.Tags = []string{"third"}
+		}
+		batch = append(batch, q)
+	}
+	_, err := s.repo.ImportQuotes(ctx, batch, false)
+	require.NoError(s.T(), err)
+	deleted, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Exporter", Quote: "Trashed quote"})
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.repo.DeleteQuote(ctx, deleted, "", nil))
+
+	var all []*storage.Quote
+	require.NoError(s.T(), s.repo.ExportQuotes(ctx, storage.QuoteFilter{}, func(q *storage.Quote) error {
+		all = append(all, q)
+		return nil
+	}))
+	assert.Len(s.T(), all, 600)
+	for _, q := range all {
+		assert.NotEqual(s.T(), deleted, q.ID)
+	}
+
+	var tagged int
+	require.NoError(s.T(), s.repo.ExportQuotes(ctx, storage.QuoteFilter{Author: "exporter", Tags: []string{"third"}}, func(q *storage.Quote) error {
+		tagged++
+		return nil
+	}))
+	assert.Equal(s.T(), 200, tagged)
+
+	stop := errors.New("stop")
+	var seen int
+	err = s.repo.ExportQuotes(ctx, storage.QuoteFilter{}, func(q *storage.Quote) error {
+		seen++
+		return stop
+	})
+	assert.ErrorIs(s.T(), err, stop)
+	assert.Equal(s.T(), 1, seen)
+}
+
+func (s *QuoteRepositoryTestSuite) TestGetAllQuotes_Pagination() {
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		_, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Paged", Quote: "Quote " + strconv.Itoa(i)})
+		require.NoError(s.T(), err)
+	}
+
+	var (
+		seen  []int64
+		after *storage.Cursor
+	)
+	for pages := 0; pages < 3; pages++ {
+		quotes, next, err := s.repo.GetAllQuotes(ctx, storage.QuoteFilter{}, storage.Page{Limit: 2, After: after})
+		require.NoError(s.T(), err)
+		for _, q := range quotes {
+			seen = append(seen, q.ID)
+		}
+		if pages < 2 {
+			require.NotNil(s.T(), next)
+		} else {
+			assert.Nil(s.T(), next)
+		}
+		after = next
+	}
+
+	require.Len(s.T(), seen, 5)
+	for i := 1; i < len(seen); i++ {
+		assert.NotEqual(s.T(), seen[i-1], seen[i])
+	}
+
+	quotes, next, err := s.repo.GetQuotesByAuthor(ctx, "Paged", storage.Page{Limit: 5})
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), quotes, 5)
+	assert.Nil(s.T(), next)
+}
+
+func (s *QuoteRepositoryTestSuite) TestUpdateQuote() {
+	ctx := context.Background()
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Typo Autor", Quote: "Original text"})
+	require.NoError(s.T(), err)
+
+	author := "Fixed Author"
+	updated, err := s.repo.UpdateQuote(ctx, id, &storage.QuoteUpdate{Author: &author})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), id, updated.ID)
+	assert.Equal(s.T(), author, updated.Author)
+	assert.Equal(s.T(), "Original text", updated.Quote)
+	assert.False(s.T(), updated.UpdatedAt.Before(updated.CreatedAt))
+
+	text := "Replaced text"
+	updated, err = s.repo.UpdateQuote(ctx, id, &storage.QuoteUpdate{Author: &author, Quote: &text})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), text, updated.Quote)
+
+	_, err = s.repo.UpdateQuote(ctx, id+1000, &storage.QuoteUpdate{Quote: &text})
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+}
+
+func (s *QuoteRepositoryTestSuite) TestTags() {
+	ctx := context.Background()
+	_, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Seneca", Quote: "Luck is what happens when preparation meets opportunity.", Tags: []string{"luck", "stoicism"}})
+	require.NoError(s.T(), err)
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Seneca", Quote: "While we wait for life, life passes.", Tags: []string{"stoicism", "time"}})
+	require.NoError(s.T(), err)
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Mark Twain", Quote: "The secret of getting ahead is getting started.", Tags: []string{"time"}})
+	require.NoError(s.T(), err)
+
+	page := storage.Page{Limit: 10}
+
+	quotes, _, err := s.repo.GetAllQuotes(ctx, storage.QuoteFilter{Tags: []string{"luck", "time"}}, page)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), quotes, 3)
+
+	quotes, _, err = s.repo.GetAllQuotes(ctx, storage.QuoteFilter{Tags: []string{"stoicism", "time"}, MatchAllTags: true}, page)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), quotes, 1)
+	assert.Equal(s.T(), []string{"stoicism", "time"}, quotes[0].Tags)
+
+	quotes, _, err = s.repo.GetAllQuotes(ctx, storage.QuoteFilter{Author: "Mark Twain", Tags: []string{"time"}}, page)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), quotes, 1)
+	assert.Equal(s.T(), "Mark Twain", quotes[0].Author)
+
+	tags, err := s.repo.GetTags(ctx)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), tags, 3)
+	assert.Equal(s.T(), storage.Tag{Name: "stoicism", Count: 2}, *tags[0])
+	assert.Equal(s.T(), storage.Tag{Name: "time", Count: 2}, *tags[1])
+	assert.Equal(s.T(), storage.Tag{Name: "luck", Count: 1}, *tags[2])
+}
+
+func (s *QuoteRepositoryTestSuite) TestAddQuote_Duplicate() {
+	ctx := context.Background()
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Oscar Wilde", Quote: "Be yourself; everyone else is already taken."})
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Wilde", Quote: "  be yourself, everyone  else is already taken!"})
+	require.ErrorIs(s.T(), err, storage.ErrConflict)
+	var dupErr *storage.DuplicateError
+	require.ErrorAs(s.T(), err, &dupErr)
+	assert.Equal(s.T(), id, dupErr.ID)
+
+	quotes, _, err := s.repo.GetAllQuotes(ctx, storage.QuoteFilter{}, storage.Page{Limit: 10})
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), quotes, 1)
+}
+
+func (s *QuoteRepositoryTestSuite) TestAddQuote_ContentHashUnicode() {
+	ctx := context.Background()
+	_, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Цицерон", Quote: "Пока дышу, надеюсь."})
+	require.NoError(s.T(), err)
+	id, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Сенека", Quote: "Пока живёшь — учись!"})
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Сенека", Quote: "  ПОКА ЖИВЁШЬ, учись"})
+	var dupErr *storage.DuplicateError
+	require.ErrorAs(s.T(), err, &dupErr)
+	assert.Equal(s.T(), id, dupErr.ID)
+
+	// Word boundaries are kept.
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Сенека", Quote: "Покаживёшь, учись"})
+	require.NoError(s.T(), err)
+}
+
+func (s *QuoteRepositoryTestSuite) TestDailyQuote() {
+	ctx := context.Background()
+	_, err := s.repo.PickDailyQuote(ctx, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Daily", Quote: "Daily quote " + strconv.Itoa(i)})
+		require.NoError(s.T(), err)
+	}
+
+	seen := map[int64]bool{}
+	for day := 1; day <= 3; day++ {
+		date := time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC)
+		q, err := s.repo.PickDailyQuote(ctx, date)
+		require.NoError(s.T(), err)
+		assert.False(s.T(), seen[q.ID], "quote repeated before the pool was exhausted")
+		seen[q.ID] = true
+
+		again, err := s.repo.PickDailyQuote(ctx, date)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), q.ID, again.ID)
+
+		stored, err := s.repo.GetDailyQuote(ctx, date)
+		require.NoError(s.T(), err)
+		assert.Equal(s.T(), q.ID, stored.ID)
+	}
+
+	_, err = s.repo.PickDailyQuote(ctx, time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(s.T(), err)
+
+	_, err = s.repo.GetDailyQuote(ctx, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
+}
+
+func (s *QuoteRepositoryTestSuite) TestAuthors() {
+	ctx := context.Background()
+	firstID, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Confucius", Quote: "Life is simple, but we insist on making it complicated."})
+	require.NoError(s.T(), err)
+	secondID, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "confucius ", Quote: "Real knowledge is to know the extent of one's ignorance."})
+	require.NoError(s.T(), err)
+
+	first, err := s.repo.GetQuoteByID(ctx, firstID)
+	require.NoError(s.T(), err)
+	second, err := s.repo.GetQuoteByID(ctx, secondID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), first.AuthorID, second.AuthorID)
+	assert.Equal(s.T(), "Confucius", second.Author)
+
+	_, err = s.db.Pool().Exec(ctx, `INSERT INTO author_aliases (alias, author_id) VALUES ('конфуций', $1)`, first.AuthorID)
+	require.NoError(s.T(), err)
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Конфуций", Quote: "Знание — это знать, что знаешь, и знать, что не знаешь."})
+	require.NoError(s.T(), err)
+
+	quotes, _, err := s.repo.GetQuotesByAuthor(ctx, "CONFUCIUS", storage.Page{Limit: 10})
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), quotes, 3)
+
+	author, err := s.repo.GetAuthorByID(ctx, first.AuthorID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "Confucius", author.Name)
+	assert.Equal(s.T(), []string{"confucius", "конфуций"}, author.Aliases)
+
+	authors, err := s.repo.GetAuthors(ctx, 10, 0)
+	require.NoError(s.T(), err)
+	assert.Len(s.T(), authors, 1)
+
+	_, err = s.repo.GetAuthorByID(ctx, first.AuthorID+1000)
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
 }
 
 func (s *QuoteRepositoryTestSuite) TestGetAllQuotes_Empty() {
 	ctx := context.Background()
-	quotes, err := s.repo.GetAllQuotes(ctx)
+	quotes, _, err := s.repo.GetAllQuotes(ctx, storage.QuoteFilter{}, storage.Page{Limit: 100})
 	require.NoError(s.T(), err)
 	assert.Empty(s.T(), quotes)
 }
 
 func (s *QuoteRepositoryTestSuite) TestGetRandomQuote_Empty() {
 	ctx := context.Background()
-	q, err := s.repo.GetRandomQuote(ctx)
-	assert.Error(s.T(), err)
+	q, err := s.repo.GetRandomQuote(ctx, storage.QuoteFilter{})
+	assert.ErrorIs(s.T(), err, storage.ErrNotFound)
 	assert.Nil(s.T(), q)
 }
 
 func (s *QuoteRepositoryTestSuite) TestGetQuotesByAuthor_Empty() {
 	ctx := context.Background()
-	quotes, err := s.repo.GetQuotesByAuthor(ctx, "NonExistingAuthor")
+	quotes, _, err := s.repo.GetQuotesByAuthor(ctx, "NonExistingAuthor", storage.Page{Limit: 100})
 	require.NoError(s.T(), err)
 	assert.Empty(s.T(), quotes)
 }
 
+func (s *QuoteRepositoryTestSuite) TestSearchQuotes() {
+	ctx := context.Background()
+	_, err := s.repo.AddQuote(ctx, &storage.Quote{Author: "Albert Einstein", Quote: "Imagination is more important than knowledge."})
+	require.NoError(s.T(), err)
+	_, err = s.repo.AddQuote(ctx, &storage.Quote{Author: "Mark Twain", Quote: "The secret of getting ahead is getting started."})
+	require.NoError(s.T(), err)
+
+	results, err := s.repo.SearchQuotes(ctx, "imagine knowledge", 10, 0)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), results, 1)
+	assert.Equal(s.T(), "Albert Einstein", results[0].Author)
+	assert.Greater(s.T(), results[0].Rank, float32(0))
+	assert.Contains(s.T(), results[0].Headline, "<b>")
+
+	results, err = s.repo.SearchQuotes(ctx, "twain", 10, 0)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), results, 1)
+	assert.Equal(s.T(), "Mark Twain", results[0].Author)
+	assert.Contains(s.T(), results[0].Headline, "<b>Twain</b>")
+
+	results, err = s.repo.SearchQuotes(ctx, "nonexistentword", 10, 0)
+	require.NoError(s.T(), err)
+	assert.Empty(s.T(), results)
+}
+
 func TestQuoteRepositorySuite(t *testing.T) {
 	suite.Run(t, new(QuoteRepositoryTestSuite))
 }
+
+func (s *QuoteRepositoryTestSuite) TestCheckHealth() {
+	health := s.db.CheckHealth(context.Background())
+
+	s.True(health.Live)
+	s.True(health.Ready)
+	s.Empty(health.Error)
+	s.Equal("postgres", health.Name)
+	s.Contains(health.Details, "total_conns")
+
+	down := storage.NewDB(&storage.Config{}, slog.Default()).CheckHealth(context.Background())
+	s.True(down.Live)
+	s.False(down.Ready)
+	s.NotEmpty(down.Error)
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetDeletedQuotes lists the trash, most recently deleted first.
+func (db *DB) GetDeletedQuotes(ctx context.Context, limit, offset int) ([]*Quote, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx,
+		`SELECT `+quoteColumns+` FROM quotes
+		 WHERE deleted_at IS NOT NULL
+		 ORDER BY deleted_at DESC, id DESC
+		 LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []*Quote
+	for rows.Next() {
+		q, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, q)
+	}
+	return quotes, rows.Err()
+}
+
+func (db *DB) GetDeletedQuoteByID(ctx context.Context, id int64) (*Quote, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	q, err := scanQuote(conn.QueryRow(ctx,
+		`SELECT `+quoteColumns+` FROM quotes WHERE id = $1 AND deleted_at IS NOT NULL`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return q, nil
+}
+
+// RestoreQuote takes a quote out of the trash. It fails with ErrConflict when
+// the same text has been added again in the meantime. Owner and versions work
+// like in DeleteQuote.
+func (db *DB) RestoreQuote(ctx context.Context, id int64, owner string, versions []int) (*Quote, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			db.log.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	before, err := getQuoteTx(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if before.DeletedAt == nil {
+		return nil, ErrNotFound
+	}
+	if err := matchVersion(before, versions); err != nil {
+		return nil, err
+	}
+
+	q, err := scanQuote(tx.QueryRow(ctx,
+		`UPDATE quotes SET deleted_at = NULL, version = version + 1
+		 WHERE id = $1 AND `+ownerCondition+`
+		 RETURNING `+quoteColumns, id, owner))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotOwner
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if err := recordAudit(ctx, tx, AuditRestore, id, before, q); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// PurgeDeletedQuotes permanently removes quotes trashed before the given time
+// and returns how many were removed.
+func (db *DB) PurgeDeletedQuotes(ctx context.Context, before time.Time) (int64, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			db.log.Error("rollback error", slog.String("err", err.Error()))
+		}
+	}()
+
+	rows, err := tx.Query(ctx,
+		`SELECT `+quoteColumns+` FROM quotes
+		 WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		 FOR UPDATE`, before)
+	if err != nil {
+		return 0, err
+	}
+	var purged []*Quote
+	for rows.Next() {
+		q, err := scanQuote(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		purged = append(purged, q)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, 0, len(purged))
+	for _, q := range purged {
+		ids = append(ids, q.ID)
+		if err := recordAudit(ctx, tx, AuditPurge, q.ID, q, nil); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM quotes WHERE id = ANY($1)`, ids); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return int64(len(purged)), nil
+}
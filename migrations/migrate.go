@@ -3,47 +3,255 @@ package migrations
 import (
 	"embed"
 	"errors"
+	"fmt"
+	"io/fs"
+
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 //go:embed sql/*
-var fs embed.FS
+var sqlFS embed.FS
 
-func PostgresMigrate(connStr string) error {
-	d, err := iofs.New(fs, "sql")
+// NilVersion passed to Migrator.Force marks the database as having no migrations applied.
+const NilVersion = database.NilVersion
+
+// Directions of a Step.
+const (
+	Up   = "up"
+	Down = "down"
+)
+
+// Step is one migration that Migrator would apply or roll back.
+type Step struct {
+	Version   uint
+	Name      string
+	Direction string
+}
+
+func (s Step) String() string {
+	return fmt.Sprintf("%04d %s (%s)", s.Version, s.Name, s.Direction)
+}
+
+// Migrator applies the embedded migrations to one database. Up, Down and
+// Goto return migrate.ErrNoChange when there is nothing to do and
+// migrate.ErrDirty when a previous migration failed half-way.
+type Migrator struct {
+	mig *migrate.Migrate
+	src source.Driver
+}
+
+// NewPostgresMigrator connects to the database at connStr.
+func NewPostgresMigrator(connStr string) (*Migrator, error) {
+	src, err := iofs.New(sqlFS, "sql")
 	if err != nil {
-		return err
+		return nil, err
 	}
+	mig, err := migrate.NewWithSourceInstance("iofs", src, connStr)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{mig: mig, src: src}, nil
+}
 
-	mig, err := migrate.NewWithSourceInstance("iofs", d, connStr)
+// NewMigrator runs the embedded migrations against an already open database
+// driver. databaseName only appears in the messages of golang-migrate.
+func NewMigrator(databaseName string, db database.Driver) (*Migrator, error) {
+	src, err := iofs.New(sqlFS, "sql")
 	if err != nil {
-		return err
+		return nil, err
+	}
+	mig, err := migrate.NewWithInstance("iofs", src, databaseName, db)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{mig: mig, src: src}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up() error {
+	return m.mig.Up()
+}
+
+// UpN applies the next n pending migrations. It returns migrate.ErrShortLimit
+// after applying the remaining ones when fewer than n are pending.
+func (m *Migrator) UpN(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("number of migrations to apply must be positive, got %d", n)
+	}
+	return m.mig.Steps(n)
+}
+
+// Down rolls back the last n applied migrations.
+func (m *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("number of migrations to roll back must be positive, got %d", n)
+	}
+	return m.mig.Steps(-n)
+}
+
+// Goto migrates up or down to version.
+func (m *Migrator) Goto(version uint) error {
+	return m.mig.Migrate(version)
+}
+
+// Force records version as applied and clean without running anything. It is
+// used to recover from a dirty state once the schema was fixed by hand;
+// NilVersion marks the database as having no migrations.
+func (m *Migrator) Force(version int) error {
+	return m.mig.Force(version)
+}
+
+// Version returns the applied version, or migrate.ErrNilVersion when no
+// migration was applied yet.
+func (m *Migrator) Version() (version uint, dirty bool, err error) {
+	return m.mig.Version()
+}
+
+// PlanUp lists the migrations Up would apply.
+func (m *Migrator) PlanUp() ([]Step, error) {
+	current, ok, err := m.current()
+	if err != nil {
+		return nil, err
 	}
-	if err := mig.Up(); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			return nil
+	var steps []Step
+	v, err := m.src.First()
+	for ; err == nil; v, err = m.src.Next(v) {
+		if !ok || v > current {
+			step, err := m.step(v, Up)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
 		}
-		return err
 	}
-	_, err = mig.Close()
-	return err
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return steps, nil
 }
-func PostgresMigrateDown(connStr string) error {
-	d, err := iofs.New(fs, "sql")
+
+// PlanUpN lists the migrations UpN(n) would apply.
+func (m *Migrator) PlanUpN(n int) ([]Step, error) {
+	steps, err := m.PlanUp()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return steps[:min(n, len(steps))], nil
+}
+
+// PlanDown lists the migrations Down(n) would roll back, newest first.
+func (m *Migrator) PlanDown(n int) ([]Step, error) {
+	current, ok, err := m.current()
+	if err != nil || !ok {
+		return nil, err
+	}
+	var steps []Step
+	v := current
+	for len(steps) < n {
+		step, err := m.step(v, Down)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+		if v, err = m.src.Prev(v); errors.Is(err, fs.ErrNotExist) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
 	}
+	return steps, nil
+}
 
-	mig, err := migrate.NewWithSourceInstance("iofs", d, connStr)
+// PlanGoto lists the migrations Goto(version) would apply or roll back.
+func (m *Migrator) PlanGoto(version uint) ([]Step, error) {
+	if _, err := m.step(version, Up); err != nil {
+		return nil, fmt.Errorf("no migration with version %d: %w", version, err)
+	}
+	current, ok, err := m.current()
+	if err != nil {
+		return nil, err
+	}
+	if !ok || version > current {
+		steps, err := m.PlanUp()
+		if err != nil {
+			return nil, err
+		}
+		for i, step := range steps {
+			if step.Version > version {
+				return steps[:i], nil
+			}
+		}
+		return steps, nil
+	}
+
+	// Rolling back stops at version, which stays applied.
+	var n int
+	for v := current; v > version; n++ {
+		if v, err = m.src.Prev(v); err != nil {
+			return nil, err
+		}
+	}
+	return m.PlanDown(n)
+}
+
+// Close releases the source and the database connection.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.mig.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// current returns the applied version; ok is false when there is none.
+func (m *Migrator) current() (version uint, ok bool, err error) {
+	version, _, err = m.mig.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return version, true, nil
+}
+
+func (m *Migrator) step(version uint, direction string) (Step, error) {
+	read := m.src.ReadUp
+	if direction == Down {
+		read = m.src.ReadDown
+	}
+	r, name, err := read(version)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Step{}, fmt.Errorf("migration %d has no %s file", version, direction)
+	}
+	if err != nil {
+		return Step{}, err
+	}
+	// nolint: errcheck
+	r.Close()
+	return Step{Version: version, Name: name, Direction: direction}, nil
+}
+
+func PostgresMigrate(connStr string) error {
+	m, err := NewPostgresMigrator(connStr)
 	if err != nil {
 		return err
 	}
-	if err := mig.Down(); err != nil {
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return errors.Join(err, m.Close())
+	}
+	return m.Close()
+}
+
+func PostgresMigrateDown(connStr string) error {
+	m, err := NewPostgresMigrator(connStr)
+	if err != nil {
 		return err
 	}
-	_, err = mig.Close()
-	return err
+	if err := m.mig.Down(); err != nil {
+		return errors.Join(err, m.Close())
+	}
+	return m.Close()
 }
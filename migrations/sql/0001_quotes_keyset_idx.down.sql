@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS quotes_author_created_at_id_idx;
+DROP INDEX IF EXISTS quotes_created_at_id_idx;
+
+ALTER TABLE quotes ALTER COLUMN created_at DROP NOT NULL;
+
+COMMIT;
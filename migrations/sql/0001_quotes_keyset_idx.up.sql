@@ -0,0 +1,9 @@
+BEGIN;
+
+UPDATE quotes SET created_at = NOW() WHERE created_at IS NULL;
+ALTER TABLE quotes ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX quotes_created_at_id_idx ON quotes (created_at DESC, id DESC);
+CREATE INDEX quotes_author_created_at_id_idx ON quotes (author, created_at DESC, id DESC);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS quotes_search_vector_idx;
+ALTER TABLE quotes DROP COLUMN IF EXISTS search_vector;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE quotes
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(author, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(quote, '')), 'B')
+    ) STORED;
+
+CREATE INDEX quotes_search_vector_idx ON quotes USING GIN (search_vector);
+
+COMMIT;
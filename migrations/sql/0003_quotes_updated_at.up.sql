@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE quotes ADD COLUMN updated_at TIMESTAMP;
+UPDATE quotes SET updated_at = created_at;
+ALTER TABLE quotes
+    ALTER COLUMN updated_at SET NOT NULL,
+    ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+
+COMMIT;
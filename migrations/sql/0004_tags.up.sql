@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE tags (
+                      id SERIAL PRIMARY KEY,
+                      name VARCHAR(64) NOT NULL UNIQUE
+);
+
+CREATE TABLE quote_tags (
+                            quote_id INTEGER NOT NULL REFERENCES quotes (id) ON DELETE CASCADE,
+                            tag_id INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+                            PRIMARY KEY (quote_id, tag_id)
+);
+
+CREATE INDEX quote_tags_tag_id_quote_id_idx ON quote_tags (tag_id, quote_id);
+
+COMMIT;
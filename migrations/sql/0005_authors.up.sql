@@ -0,0 +1,45 @@
+BEGIN;
+
+CREATE TABLE authors (
+                         id SERIAL PRIMARY KEY,
+                         name VARCHAR(255) NOT NULL,
+                         bio TEXT,
+                         birth_year INTEGER,
+                         death_year INTEGER,
+                         created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX authors_name_key ON authors (lower(name));
+
+-- Every spelling that resolves to an author, stored as lower(btrim(spelling)).
+-- The canonical name itself is always registered as an alias.
+CREATE TABLE author_aliases (
+                                alias VARCHAR(255) PRIMARY KEY,
+                                author_id INTEGER NOT NULL REFERENCES authors (id) ON DELETE CASCADE
+);
+
+CREATE INDEX author_aliases_author_id_idx ON author_aliases (author_id);
+
+-- The most frequent spelling of each author becomes the canonical name.
+INSERT INTO authors (name)
+SELECT DISTINCT ON (lower(btrim(author))) btrim(author)
+FROM quotes
+GROUP BY btrim(author)
+ORDER BY lower(btrim(author)), count(*) DESC, btrim(author);
+
+INSERT INTO author_aliases (alias, author_id)
+SELECT lower(name), id FROM authors;
+
+ALTER TABLE quotes ADD COLUMN author_id INTEGER REFERENCES authors (id);
+
+UPDATE quotes q
+SET author_id = a.id,
+    author = a.name
+FROM authors a
+WHERE lower(a.name) = lower(btrim(q.author));
+
+ALTER TABLE quotes ALTER COLUMN author_id SET NOT NULL;
+
+CREATE INDEX quotes_author_id_created_at_id_idx ON quotes (author_id, created_at DESC, id DESC);
+
+COMMIT;
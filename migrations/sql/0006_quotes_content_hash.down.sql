@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Duplicates removed by the up migration are not restored.
+DROP INDEX IF EXISTS quotes_content_hash_key;
+ALTER TABLE quotes DROP COLUMN IF EXISTS content_hash;
+DROP FUNCTION IF EXISTS quote_content_hash(text);
+
+COMMIT;
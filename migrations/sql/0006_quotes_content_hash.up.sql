@@ -0,0 +1,31 @@
+BEGIN;
+
+-- Hash of the quote text with case, whitespace and punctuation ignored,
+-- so that "Be yourself!" and "be  yourself" count as the same quote.
+CREATE FUNCTION quote_content_hash(text) RETURNS text
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+AS $$ SELECT md5(regexp_replace(lower($1), '[^[:alnum:]]+', '', 'g')) $$;
+
+ALTER TABLE quotes
+    ADD COLUMN content_hash text GENERATED ALWAYS AS (quote_content_hash(quote)) STORED;
+
+-- Existing duplicates are folded into the oldest copy, which inherits their tags.
+-- The other copies are deleted for good: the down migration cannot bring them back.
+CREATE TEMPORARY TABLE quote_duplicates ON COMMIT DROP AS
+SELECT id, min(id) OVER (PARTITION BY content_hash) AS keep_id
+FROM quotes;
+
+INSERT INTO quote_tags (quote_id, tag_id)
+SELECT d.keep_id, qt.tag_id
+FROM quote_duplicates d
+JOIN quote_tags qt ON qt.quote_id = d.id
+WHERE d.id <> d.keep_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM quotes q
+USING quote_duplicates d
+WHERE q.id = d.id AND d.id <> d.keep_id;
+
+CREATE UNIQUE INDEX quotes_content_hash_key ON quotes (content_hash);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS quotes_lang_idx;
+ALTER TABLE quotes DROP COLUMN IF EXISTS lang;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- ISO 639 language code of the quote text; NULL when unknown.
+ALTER TABLE quotes ADD COLUMN lang VARCHAR(8);
+
+CREATE INDEX quotes_lang_idx ON quotes (lang) WHERE lang IS NOT NULL;
+
+COMMIT;
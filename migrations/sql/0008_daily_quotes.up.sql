@@ -0,0 +1,14 @@
+BEGIN;
+
+-- One quote per calendar day. Quotes are drawn without repeats within a cycle;
+-- a new cycle starts once every quote has been shown in the current one.
+CREATE TABLE daily_quotes (
+                              day DATE PRIMARY KEY,
+                              quote_id INTEGER NOT NULL REFERENCES quotes (id) ON DELETE CASCADE,
+                              cycle INTEGER NOT NULL,
+                              created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX daily_quotes_cycle_quote_id_key ON daily_quotes (cycle, quote_id);
+
+COMMIT;
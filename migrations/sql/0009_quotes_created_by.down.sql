@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE quotes DROP COLUMN IF EXISTS created_by;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Subject of the principal that added the quote; NULL for quotes added before
+-- authentication existed, which only admins may delete.
+ALTER TABLE quotes ADD COLUMN created_by VARCHAR(255);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Quotes still in the trash are removed for good.
+DELETE FROM quotes WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS quotes_deleted_at_id_idx;
+DROP INDEX IF EXISTS quotes_live_id_idx;
+DROP INDEX IF EXISTS quotes_content_hash_key;
+ALTER TABLE quotes DROP COLUMN IF EXISTS deleted_at;
+CREATE UNIQUE INDEX quotes_content_hash_key ON quotes (content_hash);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE quotes ADD COLUMN deleted_at TIMESTAMP;
+
+-- A trashed quote must not block adding the same text again.
+DROP INDEX quotes_content_hash_key;
+CREATE UNIQUE INDEX quotes_content_hash_key ON quotes (content_hash) WHERE deleted_at IS NULL;
+
+-- Keeps min(id)/max(id) over live quotes cheap for random sampling.
+CREATE INDEX quotes_live_id_idx ON quotes (id) WHERE deleted_at IS NULL;
+CREATE INDEX quotes_deleted_at_id_idx ON quotes (deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- One row per quote mutation, written in the transaction of the change.
+-- quote_id has no foreign key so events outlive purged quotes.
+CREATE TABLE audit_events (
+                              id BIGSERIAL PRIMARY KEY,
+                              quote_id INTEGER NOT NULL,
+                              actor VARCHAR(255),
+                              action VARCHAR(32) NOT NULL,
+                              before JSONB,
+                              after JSONB,
+                              request_id VARCHAR(128),
+                              created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_events_quote_id_idx ON audit_events (quote_id, id DESC);
+CREATE INDEX audit_events_actor_idx ON audit_events (actor, id DESC);
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at);
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+-- Every content version of a quote. Revisions are numbered from 1 per quote
+-- and only ever grow: a rollback is stored as a new revision.
+CREATE TABLE quote_revisions (
+                                 quote_id INTEGER NOT NULL REFERENCES quotes (id) ON DELETE CASCADE,
+                                 revision INTEGER NOT NULL,
+                                 author_id INTEGER NOT NULL REFERENCES authors (id),
+                                 author VARCHAR(255) NOT NULL,
+                                 quote TEXT NOT NULL,
+                                 lang VARCHAR(8),
+                                 created_by VARCHAR(255),
+                                 created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                 PRIMARY KEY (quote_id, revision)
+);
+
+-- Existing quotes start their history at their current content.
+INSERT INTO quote_revisions (quote_id, revision, author_id, author, quote, lang, created_by, created_at)
+SELECT id, 1, author_id, author, quote, lang, created_by, updated_at
+FROM quotes;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Incremented by every change to a quote; exposed as its ETag.
+ALTER TABLE quotes ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+ALTER TABLE quotes DROP COLUMN IF EXISTS content_hash;
+
+CREATE OR REPLACE FUNCTION quote_content_hash(text) RETURNS text
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+AS $$ SELECT md5(regexp_replace(lower($1), '[^[:alnum:]]+', '', 'g')) $$;
+
+-- The old hash is coarser, so live quotes it finds equal are moved to the
+-- trash, keeping the oldest copy live. Quotes trashed by the up migration
+-- stay in the trash.
+UPDATE quotes q
+SET deleted_at = NOW(), version = q.version + 1
+FROM (
+    SELECT id, min(id) OVER (PARTITION BY quote_content_hash(quote)) AS keep_id
+    FROM quotes
+    WHERE deleted_at IS NULL
+) d
+WHERE q.id = d.id AND d.id <> d.keep_id;
+
+ALTER TABLE quotes
+    ADD COLUMN content_hash text GENERATED ALWAYS AS (quote_content_hash(quote)) STORED;
+CREATE UNIQUE INDEX quotes_content_hash_key ON quotes (content_hash) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+-- 0006 dropped every character outside [[:alnum:]] and lower-cased with the
+-- database locale, so under the C locale Cyrillic quotes all hashed to md5('')
+-- and "be yourself" matched "beyourself". Case, punctuation and whitespace are
+-- now folded with the ICU root collation, which classifies characters by
+-- Unicode category whatever the database locale is: punctuation becomes a
+-- space, whitespace runs collapse into one space and the ends are trimmed.
+ALTER TABLE quotes DROP COLUMN content_hash;
+
+CREATE OR REPLACE FUNCTION quote_content_hash(text) RETURNS text
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+AS $$
+SELECT md5(btrim(regexp_replace(
+    regexp_replace(lower($1 COLLATE "und-x-icu"), '[[:punct:]]+', ' ', 'g'),
+    '\s+', ' ', 'g')))
+$$;
+
+-- Live quotes that only the new hash finds equal are moved to the trash
+-- rather than deleted, keeping the oldest copy live.
+UPDATE quotes q
+SET deleted_at = NOW(), version = q.version + 1
+FROM (
+    SELECT id, min(id) OVER (PARTITION BY quote_content_hash(quote)) AS keep_id
+    FROM quotes
+    WHERE deleted_at IS NULL
+) d
+WHERE q.id = d.id AND d.id <> d.keep_id;
+
+ALTER TABLE quotes
+    ADD COLUMN content_hash text GENERATED ALWAYS AS (quote_content_hash(quote)) STORED;
+CREATE UNIQUE INDEX quotes_content_hash_key ON quotes (content_hash) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,246 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/azaliaz/quote-service/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/stub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStubMigrator returns a migrator over an in-memory database that has
+// version applied, or nothing when version is migrations.NilVersion.
+func newStubMigrator(t *testing.T, version int) (*migrations.Migrator, *stub.Stub) {
+	driver, err := stub.WithInstance(nil, &stub.Config{})
+	require.NoError(t, err)
+	db := driver.(*stub.Stub)
+	db.CurrentVersion = version
+
+	m, err := migrations.NewMigrator("stub", db)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, m.Close()) })
+	return m, db
+}
+
+func versions(steps []migrations.Step) []uint {
+	vs := make([]uint, 0, len(steps))
+	for _, step := range steps {
+		vs = append(vs, step.Version)
+	}
+	return vs
+}
+
+// allVersions lists every embedded migration, oldest first.
+func allVersions(t *testing.T) []uint {
+	m, _ := newStubMigrator(t, migrations.NilVersion)
+	steps, err := m.PlanUp()
+	require.NoError(t, err)
+	require.NotEmpty(t, steps)
+	return versions(steps)
+}
+
+func TestPlan(t *testing.T) {
+	all := allVersions(t)
+	require.Greater(t, len(all), 8, "the plans below need at least nine migrations")
+	assert.Equal(t, uint(0), all[0])
+	latest := int(all[len(all)-1])
+
+	tests := []struct {
+		name      string
+		current   int
+		plan      func(m *migrations.Migrator) ([]migrations.Step, error)
+		direction string
+		want      []uint
+	}{
+		{
+			name:      "up from a given version",
+			current:   5,
+			plan:      (*migrations.Migrator).PlanUp,
+			direction: migrations.Up,
+			want:      all[6:],
+		},
+		{
+			name:    "up at the latest version",
+			current: latest,
+			plan:    (*migrations.Migrator).PlanUp,
+		},
+		{
+			name:      "up N from nothing",
+			current:   migrations.NilVersion,
+			plan:      func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanUpN(2) },
+			direction: migrations.Up,
+			want:      []uint{0, 1},
+		},
+		{
+			name:      "up N from a given version",
+			current:   5,
+			plan:      func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanUpN(3) },
+			direction: migrations.Up,
+			want:      []uint{6, 7, 8},
+		},
+		{
+			name:      "up N past the latest version",
+			current:   latest - 1,
+			plan:      func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanUpN(5) },
+			direction: migrations.Up,
+			want:      []uint{uint(latest)},
+		},
+		{
+			name:      "down N",
+			current:   5,
+			plan:      func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanDown(2) },
+			direction: migrations.Down,
+			want:      []uint{5, 4},
+		},
+		{
+			name:      "down past the first migration",
+			current:   1,
+			plan:      func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanDown(5) },
+			direction: migrations.Down,
+			want:      []uint{1, 0},
+		},
+		{
+			name:    "down from nothing",
+			current: migrations.NilVersion,
+			plan:    func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanDown(1) },
+		},
+		{
+			name:      "goto a later version",
+			current:   5,
+			plan:      func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanGoto(8) },
+			direction: migrations.Up,
+			want:      []uint{6, 7, 8},
+		},
+		{
+			name:      "goto an earlier version keeps it applied",
+			current:   5,
+			plan:      func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanGoto(2) },
+			direction: migrations.Down,
+			want:      []uint{5, 4, 3},
+		},
+		{
+			name:    "goto the current version",
+			current: 5,
+			plan:    func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanGoto(5) },
+		},
+		{
+			name:      "goto from nothing",
+			current:   migrations.NilVersion,
+			plan:      func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanGoto(1) },
+			direction: migrations.Up,
+			want:      []uint{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newStubMigrator(t, tt.current)
+
+			steps, err := tt.plan(m)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, nilIfEmpty(versions(steps)))
+			for _, step := range steps {
+				assert.Equal(t, tt.direction, step.Direction)
+				assert.NotEmpty(t, step.Name)
+			}
+		})
+	}
+
+	t.Run("goto an unknown version", func(t *testing.T) {
+		m, _ := newStubMigrator(t, 5)
+
+		_, err := m.PlanGoto(uint(latest) + 100)
+		assert.Error(t, err)
+	})
+}
+
+// TestPlanMatchesRun checks that running a command applies exactly the
+// migrations its plan lists.
+func TestPlanMatchesRun(t *testing.T) {
+	tests := []struct {
+		name string
+		plan func(m *migrations.Migrator) ([]migrations.Step, error)
+		run  func(m *migrations.Migrator) error
+	}{
+		{
+			name: "up N",
+			plan: func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanUpN(2) },
+			run:  func(m *migrations.Migrator) error { return m.UpN(2) },
+		},
+		{
+			name: "down N",
+			plan: func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanDown(3) },
+			run:  func(m *migrations.Migrator) error { return m.Down(3) },
+		},
+		{
+			name: "goto",
+			plan: func(m *migrations.Migrator) ([]migrations.Step, error) { return m.PlanGoto(2) },
+			run:  func(m *migrations.Migrator) error { return m.Goto(2) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, db := newStubMigrator(t, 5)
+
+			steps, err := tt.plan(m)
+			require.NoError(t, err)
+			require.NoError(t, tt.run(m))
+			assert.Len(t, db.MigrationSequence, len(steps))
+
+			// The database ends one version before the last rolled back
+			// migration, or at the last applied one.
+			last := steps[len(steps)-1]
+			want := int(last.Version)
+			if last.Direction == migrations.Down {
+				want = int(last.Version) - 1
+			}
+			version, dirty, err := m.Version()
+			require.NoError(t, err)
+			assert.False(t, dirty)
+			assert.Equal(t, want, int(version))
+		})
+	}
+
+	t.Run("up N with fewer pending", func(t *testing.T) {
+		all := allVersions(t)
+		m, db := newStubMigrator(t, int(all[len(all)-2]))
+
+		err := m.UpN(3)
+		var short migrate.ErrShortLimit
+		assert.ErrorAs(t, err, &short)
+		assert.Len(t, db.MigrationSequence, 1)
+	})
+
+	t.Run("invalid counts", func(t *testing.T) {
+		m, db := newStubMigrator(t, 5)
+
+		assert.Error(t, m.UpN(0))
+		assert.Error(t, m.Down(-1))
+		assert.Empty(t, db.MigrationSequence)
+		assert.Equal(t, 5, db.CurrentVersion)
+	})
+
+	t.Run("force", func(t *testing.T) {
+		m, db := newStubMigrator(t, 5)
+		db.IsDirty = true
+
+		require.NoError(t, m.Force(4))
+		assert.Empty(t, db.MigrationSequence)
+		assert.Equal(t, 4, db.CurrentVersion)
+		assert.False(t, db.IsDirty)
+
+		require.NoError(t, m.Force(migrations.NilVersion))
+		_, _, err := m.Version()
+		assert.ErrorIs(t, err, migrate.ErrNilVersion)
+	})
+}
+
+func nilIfEmpty(vs []uint) []uint {
+	if len(vs) == 0 {
+		return nil
+	}
+	return vs
+}
@@ -7,8 +7,15 @@ import (
 	"os"
 	"os/signal"
 	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
+// healthTimeout bounds a single health check.
+const healthTimeout = 2 * time.Second
+
 type (
 	Service interface {
 		Init() error
@@ -18,10 +25,39 @@ type (
 	Services interface {
 		AddService(service ...Service)
 		Run(ctx context.Context) error
+		HealthReporter
 	}
 	Manager struct {
 		services []Service
 		log      *slog.Logger
+		stopping atomic.Bool
+	}
+
+	// HealthChecker is implemented by services that can report their own health.
+	HealthChecker interface {
+		CheckHealth(ctx context.Context) Health
+	}
+	// Health is the state of one component. Live is false when the component
+	// cannot recover without a restart; Ready is false while it cannot serve
+	// requests, e.g. because a dependency is unreachable.
+	Health struct {
+		Name    string
+		Live    bool
+		Ready   bool
+		Error   string
+		Details map[string]any
+	}
+	// HealthReporter aggregates the health of several components.
+	HealthReporter interface {
+		Health(ctx context.Context) HealthReport
+	}
+	// HealthReport is live when every component is, and ready when every
+	// component is and no shutdown has started.
+	HealthReport struct {
+		Live       bool
+		Ready      bool
+		Stopping   bool
+		Components []Health
 	}
 )
 
@@ -53,7 +89,7 @@ func (s *Manager) Run(ctx context.Context) (err error) {
 	s.log.Info("the worker has been initialized")
 
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
 	select {
 	case <-c:
@@ -67,9 +103,48 @@ func (s *Manager) Run(ctx context.Context) (err error) {
 	return nil
 }
 
+// stop stops the services in reverse order, so each one still has its
+// dependencies while it shuts down. Readiness fails from the first moment.
 func (s *Manager) stop() {
 	s.log.Info("going to stop")
+	s.stopping.Store(true)
+	for i := len(s.services) - 1; i >= 0; i-- {
+		s.services[i].Stop()
+	}
+}
+
+// Health runs the checks of all services implementing HealthChecker concurrently.
+func (s *Manager) Health(ctx context.Context) HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	var checkers []HealthChecker
 	for _, service := range s.services {
-		service.Stop()
+		if checker, ok := service.(HealthChecker); ok {
+			checkers = append(checkers, checker)
+		}
+	}
+
+	components := make([]Health, len(checkers))
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = checker.CheckHealth(ctx)
+		}()
+	}
+	wg.Wait()
+
+	return NewHealthReport(s.stopping.Load(), components...)
+}
+
+// NewHealthReport aggregates components into a report.
+func NewHealthReport(stopping bool, components ...Health) HealthReport {
+	report := HealthReport{Live: true, Ready: !stopping, Stopping: stopping, Components: components}
+	for _, c := range components {
+		report.Live = report.Live && c.Live
+		report.Ready = report.Ready && c.Ready
 	}
+	return report
 }